@@ -0,0 +1,96 @@
+package v1
+
+import (
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+// TestSetDefaultsDynamicArgs checks that only the fields left out of the
+// configuration are defaulted, explicit zero values survive.
+func TestSetDefaultsDynamicArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		check  func(t *testing.T, args *DynamicArgs)
+	}{
+		{
+			name:   "empty",
+			config: `{}`,
+			check: func(t *testing.T, args *DynamicArgs) {
+				if got := *args.ToleranceCPURate; got != DefaultToleranceCPURate {
+					t.Errorf("toleranceCPURate = %v, want %v", got, DefaultToleranceCPURate)
+				}
+				if got := *args.CPUWeight; got != DefaultCPUWeight {
+					t.Errorf("cpuWeight = %v, want %v", got, DefaultCPUWeight)
+				}
+				if got := *args.MetricsProvider.Prometheus.TimestampQuery; got != DefaultPrometheusTimestampQuery {
+					t.Errorf("prometheus.timestampQuery = %v, want %v", got, DefaultPrometheusTimestampQuery)
+				}
+				if args.RequestToleranceCPURate != nil {
+					t.Errorf("requestToleranceCPURate = %v, want unset", *args.RequestToleranceCPURate)
+				}
+			},
+		},
+		{
+			name:   "explicit zero tolerance",
+			config: `toleranceCPURate: 0`,
+			check: func(t *testing.T, args *DynamicArgs) {
+				if got := *args.ToleranceCPURate; got != 0 {
+					t.Errorf("toleranceCPURate = %v, want 0", got)
+				}
+				if got := *args.ToleranceMemoryRate; got != DefaultToleranceMemoryRate {
+					t.Errorf("toleranceMemoryRate = %v, want %v", got, DefaultToleranceMemoryRate)
+				}
+			},
+		},
+		{
+			name: "explicit zero weights",
+			config: `
+cpuWeight: 0
+ranking:
+  weight: 0
+nodePressure:
+  penalty: 0
+`,
+			check: func(t *testing.T, args *DynamicArgs) {
+				if got := *args.CPUWeight; got != 0 {
+					t.Errorf("cpuWeight = %v, want 0", got)
+				}
+				if got := *args.Ranking.Weight; got != 0 {
+					t.Errorf("ranking.weight = %v, want 0", got)
+				}
+				if got := *args.NodePressure.Penalty; got != 0 {
+					t.Errorf("nodePressure.penalty = %v, want 0", got)
+				}
+				if got := *args.MemoryWeight; got != DefaultMemoryWeight {
+					t.Errorf("memoryWeight = %v, want %v", got, DefaultMemoryWeight)
+				}
+			},
+		},
+		{
+			name: "explicit empty timestamp query",
+			config: `
+metricsProvider:
+  prometheus:
+    timestampQuery: ""
+`,
+			check: func(t *testing.T, args *DynamicArgs) {
+				if got := *args.MetricsProvider.Prometheus.TimestampQuery; got != "" {
+					t.Errorf("prometheus.timestampQuery = %q, want empty", got)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := &DynamicArgs{}
+			if err := yaml.UnmarshalStrict([]byte(tt.config), args); err != nil {
+				t.Fatalf("unmarshal %q err: %v", tt.config, err)
+			}
+			SetObjectDefaults_DynamicArgs(args)
+			tt.check(t, args)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package v1beta2
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -9,6 +10,387 @@ import (
 // DynamicArgs is the args struction of scheduler plugin.
 type DynamicArgs struct {
 	metav1.TypeMeta     `json:",inline"`
-	ToleranceCPURate    float64 `json:"toleranceCPURate,omitempty"`
-	ToleranceMemoryRate float64 `json:"toleranceMemoryRate,omitempty"`
+	ToleranceCPURate    *float64 `json:"toleranceCPURate,omitempty"`
+	ToleranceMemoryRate *float64 `json:"toleranceMemoryRate,omitempty"`
+
+	// CPUWeight and MemoryWeight balance real cpu usage against real
+	// memory usage when scoring nodes.
+	CPUWeight    *int64 `json:"cpuWeight,omitempty"`
+	MemoryWeight *int64 `json:"memoryWeight,omitempty"`
+
+	// Ranking orders the feasible nodes before scoring.
+	Ranking NodeRankingArgs `json:"ranking,omitempty"`
+
+	// Aggregation reduces the metrics window of a node to the usage
+	// seen by Filter and Score.
+	Aggregation MetricsAggregationArgs `json:"aggregation,omitempty"`
+
+	// MetricsProvider is the source of the real usage of nodes.
+	MetricsProvider MetricsProviderArgs `json:"metricsProvider,omitempty"`
+
+	// Scrape controls how often and how fast node metrics are fetched.
+	Scrape ScrapeArgs `json:"scrape,omitempty"`
+
+	// Staleness decides when node metrics are too old to be trusted.
+	Staleness StalenessArgs `json:"staleness,omitempty"`
+
+	// ToleranceOverrides replace ToleranceCPURate and ToleranceMemoryRate
+	// for the pods they match. The first matching override wins.
+	ToleranceOverrides []ToleranceOverride `json:"toleranceOverrides,omitempty"`
+
+	// NodeProfiles replace ToleranceCPURate and ToleranceMemoryRate for the
+	// nodes they select. The first matching profile wins.
+	NodeProfiles []NodeToleranceProfile `json:"nodeProfiles,omitempty"`
+	// NodeAnnotationOverrides lets the dynamic.scheduler/cpu-tolerance and
+	// dynamic.scheduler/memory-tolerance node annotations set the
+	// tolerances of their node.
+	//
+	// Tolerances are taken from, in order of precedence: the node
+	// annotations, the first matching ToleranceOverrides, the first
+	// matching NodeProfiles, ToleranceCPURate and ToleranceMemoryRate.
+	NodeAnnotationOverrides *bool `json:"nodeAnnotationOverrides,omitempty"`
+
+	// RequestToleranceCPURate and RequestToleranceMemoryRate cap the request
+	// rates of a node, the incoming pod included, whatever its real usage.
+	// Unset disables the check.
+	RequestToleranceCPURate    *float64 `json:"requestToleranceCPURate,omitempty"`
+	RequestToleranceMemoryRate *float64 `json:"requestToleranceMemoryRate,omitempty"`
+	// OvercommitRatio scales the request tolerances, 1.5 lets requests reach
+	// 150% of the request tolerances.
+	OvercommitRatio *float64 `json:"overcommitRatio,omitempty"`
+
+	// PodUsageEstimation estimates the real usage of the incoming pod, which
+	// Filter adds to the real usage of the node.
+	PodUsageEstimation PodUsageEstimationArgs `json:"podUsageEstimation,omitempty"`
+
+	// Resources are extra resources, such as ephemeral-storage, hugepages
+	// or extended resources, whose rates are held to their own tolerances.
+	Resources []ResourceTolerance `json:"resources,omitempty"`
+
+	// NodePressure folds the MemoryPressure, DiskPressure and PIDPressure
+	// conditions and taints of the nodes into Filter and Score.
+	NodePressure NodePressureArgs `json:"nodePressure,omitempty"`
+
+	// Events on pods rejected by every node and on nodes turning hot or
+	// cooling down.
+	Events EventsArgs `json:"events,omitempty"`
+
+	// Debug serves the node cache state.
+	Debug DebugArgs `json:"debug,omitempty"`
+
+	// Mode is Enforce or Shadow, see DynamicMode.
+	Mode DynamicMode `json:"mode,omitempty"`
+
+	// Degradation holds the policy while the metrics provider is down.
+	Degradation DegradationArgs `json:"degradation,omitempty"`
+
+	// CacheSyncTimeout bounds each wait for the node and pod informers to
+	// sync, the readiness check fails once it is exceeded.
+	CacheSyncTimeout *metav1.Duration `json:"cacheSyncTimeout,omitempty"`
+}
+
+// DynamicMode decides whether the Filter decisions take effect.
+type DynamicMode string
+
+const (
+	// EnforceMode rejects the nodes that fail Filter.
+	EnforceMode DynamicMode = "Enforce"
+	// ShadowMode only logs and records the Filter decisions, in metrics and
+	// in the ShadowDecisionAnnotation of the pod, and lets every node pass.
+	// The annotation is written at Reserve, or at PostFilter when the pod
+	// got no node, so Dynamic must be enabled at both.
+	ShadowMode DynamicMode = "Shadow"
+)
+
+// RankingDimension is a node usage rate used to rank nodes.
+type RankingDimension string
+
+const (
+	RealCPURanking       RankingDimension = "RealCPU"
+	RealMemoryRanking    RankingDimension = "RealMemory"
+	RequestCPURanking    RankingDimension = "RequestCPU"
+	RequestMemoryRanking RankingDimension = "RequestMemory"
+)
+
+// RankingDirection decides whether the least or the most loaded node wins.
+type RankingDirection string
+
+const (
+	// SpreadRanking prefers the least loaded nodes.
+	SpreadRanking RankingDirection = "Spread"
+	// BinPackRanking prefers the most loaded nodes.
+	BinPackRanking RankingDirection = "BinPack"
+)
+
+// RankingRule compares nodes on one dimension. Rates that fall into the
+// same Epsilon wide bucket are equal and the next rule decides.
+type RankingRule struct {
+	Dimension RankingDimension `json:"dimension"`
+	Epsilon   float64          `json:"epsilon,omitempty"`
+}
+
+// NodeRankingArgs holds the node ranking policy.
+type NodeRankingArgs struct {
+	Direction RankingDirection `json:"direction,omitempty"`
+	// Rules are applied in order.
+	Rules []RankingRule `json:"rules,omitempty"`
+	// Weight of the ranking in the node score, relative to CPUWeight
+	// and MemoryWeight.
+	Weight *int64 `json:"weight,omitempty"`
+}
+
+// AggregationType selects how the metrics window of a node is reduced.
+type AggregationType string
+
+const (
+	// LatestAggregation uses the last sample only.
+	LatestAggregation AggregationType = "Latest"
+	MeanAggregation   AggregationType = "Mean"
+	MaxAggregation    AggregationType = "Max"
+	P90Aggregation    AggregationType = "P90"
+	P95Aggregation    AggregationType = "P95"
+	// EWMAAggregation weights every sample by its age, the weight halves
+	// every HalfLife.
+	EWMAAggregation AggregationType = "EWMA"
+)
+
+// MetricsAggregationArgs holds the metrics window aggregation policy.
+type MetricsAggregationArgs struct {
+	Type AggregationType `json:"type,omitempty"`
+	// HalfLife of the EWMA aggregation.
+	HalfLife *metav1.Duration `json:"halfLife,omitempty"`
+}
+
+// MetricsProviderType selects where the real usage of nodes comes from.
+type MetricsProviderType string
+
+const (
+	// MetricsServerProvider reads the metrics.k8s.io api.
+	MetricsServerProvider MetricsProviderType = "MetricsServer"
+	// PrometheusProvider runs PromQL queries against a Prometheus server.
+	PrometheusProvider MetricsProviderType = "Prometheus"
+)
+
+// MetricsProviderArgs holds the metrics provider configuration.
+type MetricsProviderArgs struct {
+	Type       MetricsProviderType `json:"type,omitempty"`
+	Prometheus PrometheusArgs      `json:"prometheus,omitempty"`
+}
+
+// PrometheusArgs holds the Prometheus metrics provider configuration.
+type PrometheusArgs struct {
+	// Address of the Prometheus server, e.g. http://prometheus:9090.
+	Address string `json:"address,omitempty"`
+	// CPUQuery and MemoryQuery return the cpu usage in cores and the
+	// memory usage in bytes of the node named by $node. The default
+	// queries read node-exporter series and match the node on a node
+	// label holding the Kubernetes node name, e.g. relabeled from
+	// __meta_kubernetes_pod_node_name. The instance label of
+	// node-exporter is host:port and doesn't match.
+	CPUQuery    string `json:"cpuQuery,omitempty"`
+	MemoryQuery string `json:"memoryQuery,omitempty"`
+	// Timeout of each query.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// ResourceQueries return the usage of the extra resources of the node
+	// named by $node, in bytes for storage and hugepages.
+	ResourceQueries []PrometheusResourceQuery `json:"resourceQueries,omitempty"`
+	// TimestampQuery returns the unix time of the latest raw sample of the
+	// node named by $node, the time of the usage sample that the staleness
+	// and degradation checks go by. Instant queries are stamped with their
+	// evaluation time, so when empty the usage never looks stale.
+	TimestampQuery *string `json:"timestampQuery,omitempty"`
+}
+
+// ScrapeArgs holds the node metrics scraping configuration.
+type ScrapeArgs struct {
+	// Interval between two scrapes of all nodes.
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// PageSize of the list call for providers that list all nodes at once.
+	PageSize *int64 `json:"pageSize,omitempty"`
+	// Concurrency and QPS bound the per-node requests for providers that
+	// can't list.
+	Concurrency *int32   `json:"concurrency,omitempty"`
+	QPS         *float64 `json:"qps,omitempty"`
+}
+
+// StaleMetricsPolicy decides how nodes with missing or stale metrics are
+// treated.
+type StaleMetricsPolicy string
+
+const (
+	// RejectStaleMetrics filters the node out.
+	RejectStaleMetrics StaleMetricsPolicy = "Reject"
+	// AdmitStaleMetrics lets the node pass Filter.
+	AdmitStaleMetrics StaleMetricsPolicy = "Admit"
+	// RequestBasedStaleMetrics uses the request rates as the real rates.
+	RequestBasedStaleMetrics StaleMetricsPolicy = "RequestBased"
+)
+
+// StalenessArgs holds the node metrics staleness policy.
+type StalenessArgs struct {
+	// MaxAge of a metrics sample, older samples are ignored.
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+	// Policy for nodes without any sample younger than MaxAge.
+	Policy StaleMetricsPolicy `json:"policy,omitempty"`
+}
+
+// NodePressurePolicy decides how nodes under pressure are treated.
+type NodePressurePolicy string
+
+const (
+	// IgnoreNodePressure leaves the nodes under pressure to the other
+	// plugins.
+	IgnoreNodePressure NodePressurePolicy = "Ignore"
+	// RejectNodePressure filters the node out.
+	RejectNodePressure NodePressurePolicy = "Reject"
+	// PenalizeNodePressure lowers the score of the node by Penalty.
+	PenalizeNodePressure NodePressurePolicy = "Penalize"
+)
+
+// NodePressureArgs holds the node pressure policy.
+type NodePressureArgs struct {
+	Policy NodePressurePolicy `json:"policy,omitempty"`
+	// Penalty is subtracted from the score of the nodes under pressure.
+	Penalty *int64 `json:"penalty,omitempty"`
+	// Cooldown keeps a node under pressure for this long after its last
+	// pressure was seen, so that flapping nodes stay hot.
+	Cooldown *metav1.Duration `json:"cooldown,omitempty"`
+}
+
+// EventsArgs holds the Event policy.
+type EventsArgs struct {
+	// Interval is the minimum time between two Events, or two patches of
+	// the DynamicScheduling condition, on the same object.
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// DebugArgs holds the debug and health endpoint settings.
+//
+// The node cache lives until the scheduler exits: kube-scheduler v1.26 has
+// no shutdown hook for plugins. Readiness doesn't gate leader election
+// either, a leader whose cache is not ready yet treats the nodes as
+// degraded until the first scrape, see DegradationArgs.
+type DebugArgs struct {
+	// BindAddress of the read-only debug endpoint, which also serves the
+	// /healthz and /readyz checks of the node cache, e.g. 127.0.0.1:10260.
+	// The node dump and the ?pod= Filter runs are unauthenticated, keep it
+	// on loopback. Profiles binding the same address share the endpoint:
+	// the checks cover the node caches of all of them and the node dump is
+	// the one of the first profile. Empty disables it.
+	BindAddress string `json:"bindAddress,omitempty"`
+	// HealthBindAddress of an endpoint serving only the /healthz and
+	// /readyz checks of the node cache, e.g. 0.0.0.0:10261 for the kubelet
+	// probes. It must differ from BindAddress, profiles binding the same
+	// address share it. Empty disables it.
+	HealthBindAddress string `json:"healthBindAddress,omitempty"`
+}
+
+// DegradationPolicy decides how nodes are treated while the metrics
+// provider is down.
+type DegradationPolicy string
+
+const (
+	// PassThroughDegradation lets the nodes pass the real usage checks.
+	PassThroughDegradation DegradationPolicy = "PassThrough"
+	// RequestBasedDegradation uses the request rates as the real rates.
+	RequestBasedDegradation DegradationPolicy = "RequestBased"
+	// LastKnownGoodDegradation keeps using the samples younger than
+	// LastKnownGoodTTL, whatever the MaxAge, then the request rates.
+	LastKnownGoodDegradation DegradationPolicy = "LastKnownGood"
+)
+
+// DegradationArgs holds the degraded mode policy.
+type DegradationArgs struct {
+	Policy DegradationPolicy `json:"policy,omitempty"`
+	// FailureThreshold is the number of consecutive failed node metrics
+	// scrapes after which the metrics provider is down. The first
+	// successful scrape ends the degraded mode.
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+	// LastKnownGoodTTL is the max age of the samples of the LastKnownGood
+	// policy.
+	LastKnownGoodTTL *metav1.Duration `json:"lastKnownGoodTTL,omitempty"`
+}
+
+// ToleranceOverride matches pods on every criterion that is set.
+type ToleranceOverride struct {
+	// Namespaces of the pod, empty matches any namespace.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// LabelSelector over the pod labels, nil matches any pod.
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+	// PriorityClassNames of the pod, empty matches any priority class.
+	PriorityClassNames []string `json:"priorityClassNames,omitempty"`
+	// QOSClasses of the pod, empty matches any QoS class.
+	QOSClasses []corev1.PodQOSClass `json:"qosClasses,omitempty"`
+
+	// ToleranceCPURate and ToleranceMemoryRate for the matched pods, an
+	// unset one falls back to the matching NodeProfiles, else to the global
+	// tolerance.
+	ToleranceCPURate    *float64 `json:"toleranceCPURate,omitempty"`
+	ToleranceMemoryRate *float64 `json:"toleranceMemoryRate,omitempty"`
+}
+
+// NodeToleranceProfile sets the tolerances of the nodes it selects.
+type NodeToleranceProfile struct {
+	// NodeSelector over the node labels.
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector"`
+
+	// ToleranceCPURate and ToleranceMemoryRate for the selected nodes, an
+	// unset one falls back to the global tolerance.
+	ToleranceCPURate    *float64 `json:"toleranceCPURate,omitempty"`
+	ToleranceMemoryRate *float64 `json:"toleranceMemoryRate,omitempty"`
+}
+
+// PodUsageEstimatorType selects how the usage of the incoming pod is
+// estimated.
+type PodUsageEstimatorType string
+
+const (
+	// NoneEstimator ignores the incoming pod.
+	NoneEstimator PodUsageEstimatorType = "None"
+	// RequestEstimator expects the pod to use its requests.
+	RequestEstimator PodUsageEstimatorType = "Request"
+	// RatioEstimator expects the pod to use UsageRequestRatio of its
+	// requests.
+	RatioEstimator PodUsageEstimatorType = "Ratio"
+	// HistoryEstimator expects the pod to use what the other replicas of
+	// its owner Deployment, StatefulSet or Job use, and falls back to the
+	// Ratio estimator for workloads without history. It needs the
+	// MetricsServer metrics provider, the only one reporting pod usage.
+	HistoryEstimator PodUsageEstimatorType = "History"
+)
+
+// HistoryStatistic selects the replica usage the History estimator uses.
+type HistoryStatistic string
+
+const (
+	MeanHistoryStatistic HistoryStatistic = "Mean"
+	PeakHistoryStatistic HistoryStatistic = "Peak"
+)
+
+// PodUsageEstimationArgs holds the incoming pod usage estimation policy.
+type PodUsageEstimationArgs struct {
+	Estimator PodUsageEstimatorType `json:"estimator,omitempty"`
+	// UsageRequestRatio is the expected usage over requests of the Ratio
+	// estimator.
+	UsageRequestRatio *float64 `json:"usageRequestRatio,omitempty"`
+	// HistoryStatistic of the History estimator.
+	HistoryStatistic HistoryStatistic `json:"historyStatistic,omitempty"`
+}
+
+// ResourceTolerance holds the tolerances of an extra resource. The real
+// rate is only checked if the metrics provider reports the resource.
+type ResourceTolerance struct {
+	Name corev1.ResourceName `json:"name"`
+	// ToleranceRate of the real usage over capacity, unset disables the
+	// check.
+	ToleranceRate *float64 `json:"toleranceRate,omitempty"`
+	// RequestToleranceRate of the requests over allocatable, the incoming
+	// pod included. Unset disables the check.
+	RequestToleranceRate *float64 `json:"requestToleranceRate,omitempty"`
+}
+
+// PrometheusResourceQuery is the PromQL query of an extra resource.
+type PrometheusResourceQuery struct {
+	Name  corev1.ResourceName `json:"name"`
+	Query string              `json:"query"`
 }
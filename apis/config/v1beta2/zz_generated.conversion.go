@@ -22,7 +22,11 @@ limitations under the License.
 package v1beta2
 
 import (
+	unsafe "unsafe"
+
 	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -34,6 +38,26 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*DebugArgs)(nil), (*config.DebugArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_DebugArgs_To_config_DebugArgs(a.(*DebugArgs), b.(*config.DebugArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.DebugArgs)(nil), (*DebugArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_DebugArgs_To_v1beta2_DebugArgs(a.(*config.DebugArgs), b.(*DebugArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DegradationArgs)(nil), (*config.DegradationArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_DegradationArgs_To_config_DegradationArgs(a.(*DegradationArgs), b.(*config.DegradationArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.DegradationArgs)(nil), (*DegradationArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_DegradationArgs_To_v1beta2_DegradationArgs(a.(*config.DegradationArgs), b.(*DegradationArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*DynamicArgs)(nil), (*config.DynamicArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_DynamicArgs_To_config_DynamicArgs(a.(*DynamicArgs), b.(*config.DynamicArgs), scope)
 	}); err != nil {
@@ -44,12 +68,261 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*EventsArgs)(nil), (*config.EventsArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_EventsArgs_To_config_EventsArgs(a.(*EventsArgs), b.(*config.EventsArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.EventsArgs)(nil), (*EventsArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_EventsArgs_To_v1beta2_EventsArgs(a.(*config.EventsArgs), b.(*EventsArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*MetricsAggregationArgs)(nil), (*config.MetricsAggregationArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_MetricsAggregationArgs_To_config_MetricsAggregationArgs(a.(*MetricsAggregationArgs), b.(*config.MetricsAggregationArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.MetricsAggregationArgs)(nil), (*MetricsAggregationArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_MetricsAggregationArgs_To_v1beta2_MetricsAggregationArgs(a.(*config.MetricsAggregationArgs), b.(*MetricsAggregationArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*MetricsProviderArgs)(nil), (*config.MetricsProviderArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_MetricsProviderArgs_To_config_MetricsProviderArgs(a.(*MetricsProviderArgs), b.(*config.MetricsProviderArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.MetricsProviderArgs)(nil), (*MetricsProviderArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_MetricsProviderArgs_To_v1beta2_MetricsProviderArgs(a.(*config.MetricsProviderArgs), b.(*MetricsProviderArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodePressureArgs)(nil), (*config.NodePressureArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NodePressureArgs_To_config_NodePressureArgs(a.(*NodePressureArgs), b.(*config.NodePressureArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.NodePressureArgs)(nil), (*NodePressureArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_NodePressureArgs_To_v1beta2_NodePressureArgs(a.(*config.NodePressureArgs), b.(*NodePressureArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodeRankingArgs)(nil), (*config.NodeRankingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NodeRankingArgs_To_config_NodeRankingArgs(a.(*NodeRankingArgs), b.(*config.NodeRankingArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.NodeRankingArgs)(nil), (*NodeRankingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_NodeRankingArgs_To_v1beta2_NodeRankingArgs(a.(*config.NodeRankingArgs), b.(*NodeRankingArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodeToleranceProfile)(nil), (*config.NodeToleranceProfile)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_NodeToleranceProfile_To_config_NodeToleranceProfile(a.(*NodeToleranceProfile), b.(*config.NodeToleranceProfile), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.NodeToleranceProfile)(nil), (*NodeToleranceProfile)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_NodeToleranceProfile_To_v1beta2_NodeToleranceProfile(a.(*config.NodeToleranceProfile), b.(*NodeToleranceProfile), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PodUsageEstimationArgs)(nil), (*config.PodUsageEstimationArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_PodUsageEstimationArgs_To_config_PodUsageEstimationArgs(a.(*PodUsageEstimationArgs), b.(*config.PodUsageEstimationArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.PodUsageEstimationArgs)(nil), (*PodUsageEstimationArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_PodUsageEstimationArgs_To_v1beta2_PodUsageEstimationArgs(a.(*config.PodUsageEstimationArgs), b.(*PodUsageEstimationArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PrometheusArgs)(nil), (*config.PrometheusArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_PrometheusArgs_To_config_PrometheusArgs(a.(*PrometheusArgs), b.(*config.PrometheusArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.PrometheusArgs)(nil), (*PrometheusArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_PrometheusArgs_To_v1beta2_PrometheusArgs(a.(*config.PrometheusArgs), b.(*PrometheusArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PrometheusResourceQuery)(nil), (*config.PrometheusResourceQuery)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_PrometheusResourceQuery_To_config_PrometheusResourceQuery(a.(*PrometheusResourceQuery), b.(*config.PrometheusResourceQuery), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.PrometheusResourceQuery)(nil), (*PrometheusResourceQuery)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_PrometheusResourceQuery_To_v1beta2_PrometheusResourceQuery(a.(*config.PrometheusResourceQuery), b.(*PrometheusResourceQuery), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RankingRule)(nil), (*config.RankingRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_RankingRule_To_config_RankingRule(a.(*RankingRule), b.(*config.RankingRule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.RankingRule)(nil), (*RankingRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_RankingRule_To_v1beta2_RankingRule(a.(*config.RankingRule), b.(*RankingRule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ResourceTolerance)(nil), (*config.ResourceTolerance)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_ResourceTolerance_To_config_ResourceTolerance(a.(*ResourceTolerance), b.(*config.ResourceTolerance), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ResourceTolerance)(nil), (*ResourceTolerance)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ResourceTolerance_To_v1beta2_ResourceTolerance(a.(*config.ResourceTolerance), b.(*ResourceTolerance), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ScrapeArgs)(nil), (*config.ScrapeArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_ScrapeArgs_To_config_ScrapeArgs(a.(*ScrapeArgs), b.(*config.ScrapeArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ScrapeArgs)(nil), (*ScrapeArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ScrapeArgs_To_v1beta2_ScrapeArgs(a.(*config.ScrapeArgs), b.(*ScrapeArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*StalenessArgs)(nil), (*config.StalenessArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_StalenessArgs_To_config_StalenessArgs(a.(*StalenessArgs), b.(*config.StalenessArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.StalenessArgs)(nil), (*StalenessArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_StalenessArgs_To_v1beta2_StalenessArgs(a.(*config.StalenessArgs), b.(*StalenessArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ToleranceOverride)(nil), (*config.ToleranceOverride)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_ToleranceOverride_To_config_ToleranceOverride(a.(*ToleranceOverride), b.(*config.ToleranceOverride), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ToleranceOverride)(nil), (*ToleranceOverride)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ToleranceOverride_To_v1beta2_ToleranceOverride(a.(*config.ToleranceOverride), b.(*ToleranceOverride), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
+func autoConvert_v1beta2_DebugArgs_To_config_DebugArgs(in *DebugArgs, out *config.DebugArgs, s conversion.Scope) error {
+	out.BindAddress = in.BindAddress
+	out.HealthBindAddress = in.HealthBindAddress
+	return nil
+}
+
+// Convert_v1beta2_DebugArgs_To_config_DebugArgs is an autogenerated conversion function.
+func Convert_v1beta2_DebugArgs_To_config_DebugArgs(in *DebugArgs, out *config.DebugArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_DebugArgs_To_config_DebugArgs(in, out, s)
+}
+
+func autoConvert_config_DebugArgs_To_v1beta2_DebugArgs(in *config.DebugArgs, out *DebugArgs, s conversion.Scope) error {
+	out.BindAddress = in.BindAddress
+	out.HealthBindAddress = in.HealthBindAddress
+	return nil
+}
+
+// Convert_config_DebugArgs_To_v1beta2_DebugArgs is an autogenerated conversion function.
+func Convert_config_DebugArgs_To_v1beta2_DebugArgs(in *config.DebugArgs, out *DebugArgs, s conversion.Scope) error {
+	return autoConvert_config_DebugArgs_To_v1beta2_DebugArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_DegradationArgs_To_config_DegradationArgs(in *DegradationArgs, out *config.DegradationArgs, s conversion.Scope) error {
+	out.Policy = config.DegradationPolicy(in.Policy)
+	if err := v1.Convert_Pointer_int32_To_int32(&in.FailureThreshold, &out.FailureThreshold, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.LastKnownGoodTTL, &out.LastKnownGoodTTL, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_DegradationArgs_To_config_DegradationArgs is an autogenerated conversion function.
+func Convert_v1beta2_DegradationArgs_To_config_DegradationArgs(in *DegradationArgs, out *config.DegradationArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_DegradationArgs_To_config_DegradationArgs(in, out, s)
+}
+
+func autoConvert_config_DegradationArgs_To_v1beta2_DegradationArgs(in *config.DegradationArgs, out *DegradationArgs, s conversion.Scope) error {
+	out.Policy = DegradationPolicy(in.Policy)
+	if err := v1.Convert_int32_To_Pointer_int32(&in.FailureThreshold, &out.FailureThreshold, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.LastKnownGoodTTL, &out.LastKnownGoodTTL, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_DegradationArgs_To_v1beta2_DegradationArgs is an autogenerated conversion function.
+func Convert_config_DegradationArgs_To_v1beta2_DegradationArgs(in *config.DegradationArgs, out *DegradationArgs, s conversion.Scope) error {
+	return autoConvert_config_DegradationArgs_To_v1beta2_DegradationArgs(in, out, s)
+}
+
 func autoConvert_v1beta2_DynamicArgs_To_config_DynamicArgs(in *DynamicArgs, out *config.DynamicArgs, s conversion.Scope) error {
-	out.ToleranceCPURate = in.ToleranceCPURate
-	out.ToleranceMemoryRate = in.ToleranceMemoryRate
+	if err := v1.Convert_Pointer_float64_To_float64(&in.ToleranceCPURate, &out.ToleranceCPURate, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_float64_To_float64(&in.ToleranceMemoryRate, &out.ToleranceMemoryRate, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int64_To_int64(&in.CPUWeight, &out.CPUWeight, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int64_To_int64(&in.MemoryWeight, &out.MemoryWeight, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta2_NodeRankingArgs_To_config_NodeRankingArgs(&in.Ranking, &out.Ranking, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta2_MetricsAggregationArgs_To_config_MetricsAggregationArgs(&in.Aggregation, &out.Aggregation, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta2_MetricsProviderArgs_To_config_MetricsProviderArgs(&in.MetricsProvider, &out.MetricsProvider, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta2_ScrapeArgs_To_config_ScrapeArgs(&in.Scrape, &out.Scrape, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta2_StalenessArgs_To_config_StalenessArgs(&in.Staleness, &out.Staleness, s); err != nil {
+		return err
+	}
+	out.ToleranceOverrides = *(*[]config.ToleranceOverride)(unsafe.Pointer(&in.ToleranceOverrides))
+	out.NodeProfiles = *(*[]config.NodeToleranceProfile)(unsafe.Pointer(&in.NodeProfiles))
+	if err := v1.Convert_Pointer_bool_To_bool(&in.NodeAnnotationOverrides, &out.NodeAnnotationOverrides, s); err != nil {
+		return err
+	}
+	out.RequestToleranceCPURate = (*float64)(unsafe.Pointer(in.RequestToleranceCPURate))
+	out.RequestToleranceMemoryRate = (*float64)(unsafe.Pointer(in.RequestToleranceMemoryRate))
+	if err := v1.Convert_Pointer_float64_To_float64(&in.OvercommitRatio, &out.OvercommitRatio, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta2_PodUsageEstimationArgs_To_config_PodUsageEstimationArgs(&in.PodUsageEstimation, &out.PodUsageEstimation, s); err != nil {
+		return err
+	}
+	out.Resources = *(*[]config.ResourceTolerance)(unsafe.Pointer(&in.Resources))
+	if err := Convert_v1beta2_NodePressureArgs_To_config_NodePressureArgs(&in.NodePressure, &out.NodePressure, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta2_EventsArgs_To_config_EventsArgs(&in.Events, &out.Events, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta2_DebugArgs_To_config_DebugArgs(&in.Debug, &out.Debug, s); err != nil {
+		return err
+	}
+	out.Mode = config.DynamicMode(in.Mode)
+	if err := Convert_v1beta2_DegradationArgs_To_config_DegradationArgs(&in.Degradation, &out.Degradation, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.CacheSyncTimeout, &out.CacheSyncTimeout, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -59,8 +332,63 @@ func Convert_v1beta2_DynamicArgs_To_config_DynamicArgs(in *DynamicArgs, out *con
 }
 
 func autoConvert_config_DynamicArgs_To_v1beta2_DynamicArgs(in *config.DynamicArgs, out *DynamicArgs, s conversion.Scope) error {
-	out.ToleranceCPURate = in.ToleranceCPURate
-	out.ToleranceMemoryRate = in.ToleranceMemoryRate
+	if err := v1.Convert_float64_To_Pointer_float64(&in.ToleranceCPURate, &out.ToleranceCPURate, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_float64_To_Pointer_float64(&in.ToleranceMemoryRate, &out.ToleranceMemoryRate, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int64_To_Pointer_int64(&in.CPUWeight, &out.CPUWeight, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int64_To_Pointer_int64(&in.MemoryWeight, &out.MemoryWeight, s); err != nil {
+		return err
+	}
+	if err := Convert_config_NodeRankingArgs_To_v1beta2_NodeRankingArgs(&in.Ranking, &out.Ranking, s); err != nil {
+		return err
+	}
+	if err := Convert_config_MetricsAggregationArgs_To_v1beta2_MetricsAggregationArgs(&in.Aggregation, &out.Aggregation, s); err != nil {
+		return err
+	}
+	if err := Convert_config_MetricsProviderArgs_To_v1beta2_MetricsProviderArgs(&in.MetricsProvider, &out.MetricsProvider, s); err != nil {
+		return err
+	}
+	if err := Convert_config_ScrapeArgs_To_v1beta2_ScrapeArgs(&in.Scrape, &out.Scrape, s); err != nil {
+		return err
+	}
+	if err := Convert_config_StalenessArgs_To_v1beta2_StalenessArgs(&in.Staleness, &out.Staleness, s); err != nil {
+		return err
+	}
+	out.ToleranceOverrides = *(*[]ToleranceOverride)(unsafe.Pointer(&in.ToleranceOverrides))
+	out.NodeProfiles = *(*[]NodeToleranceProfile)(unsafe.Pointer(&in.NodeProfiles))
+	if err := v1.Convert_bool_To_Pointer_bool(&in.NodeAnnotationOverrides, &out.NodeAnnotationOverrides, s); err != nil {
+		return err
+	}
+	out.RequestToleranceCPURate = (*float64)(unsafe.Pointer(in.RequestToleranceCPURate))
+	out.RequestToleranceMemoryRate = (*float64)(unsafe.Pointer(in.RequestToleranceMemoryRate))
+	if err := v1.Convert_float64_To_Pointer_float64(&in.OvercommitRatio, &out.OvercommitRatio, s); err != nil {
+		return err
+	}
+	if err := Convert_config_PodUsageEstimationArgs_To_v1beta2_PodUsageEstimationArgs(&in.PodUsageEstimation, &out.PodUsageEstimation, s); err != nil {
+		return err
+	}
+	out.Resources = *(*[]ResourceTolerance)(unsafe.Pointer(&in.Resources))
+	if err := Convert_config_NodePressureArgs_To_v1beta2_NodePressureArgs(&in.NodePressure, &out.NodePressure, s); err != nil {
+		return err
+	}
+	if err := Convert_config_EventsArgs_To_v1beta2_EventsArgs(&in.Events, &out.Events, s); err != nil {
+		return err
+	}
+	if err := Convert_config_DebugArgs_To_v1beta2_DebugArgs(&in.Debug, &out.Debug, s); err != nil {
+		return err
+	}
+	out.Mode = DynamicMode(in.Mode)
+	if err := Convert_config_DegradationArgs_To_v1beta2_DegradationArgs(&in.Degradation, &out.Degradation, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.CacheSyncTimeout, &out.CacheSyncTimeout, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -68,3 +396,395 @@ func autoConvert_config_DynamicArgs_To_v1beta2_DynamicArgs(in *config.DynamicArg
 func Convert_config_DynamicArgs_To_v1beta2_DynamicArgs(in *config.DynamicArgs, out *DynamicArgs, s conversion.Scope) error {
 	return autoConvert_config_DynamicArgs_To_v1beta2_DynamicArgs(in, out, s)
 }
+
+func autoConvert_v1beta2_EventsArgs_To_config_EventsArgs(in *EventsArgs, out *config.EventsArgs, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.Interval, &out.Interval, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_EventsArgs_To_config_EventsArgs is an autogenerated conversion function.
+func Convert_v1beta2_EventsArgs_To_config_EventsArgs(in *EventsArgs, out *config.EventsArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_EventsArgs_To_config_EventsArgs(in, out, s)
+}
+
+func autoConvert_config_EventsArgs_To_v1beta2_EventsArgs(in *config.EventsArgs, out *EventsArgs, s conversion.Scope) error {
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.Interval, &out.Interval, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_EventsArgs_To_v1beta2_EventsArgs is an autogenerated conversion function.
+func Convert_config_EventsArgs_To_v1beta2_EventsArgs(in *config.EventsArgs, out *EventsArgs, s conversion.Scope) error {
+	return autoConvert_config_EventsArgs_To_v1beta2_EventsArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_MetricsAggregationArgs_To_config_MetricsAggregationArgs(in *MetricsAggregationArgs, out *config.MetricsAggregationArgs, s conversion.Scope) error {
+	out.Type = config.AggregationType(in.Type)
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.HalfLife, &out.HalfLife, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_MetricsAggregationArgs_To_config_MetricsAggregationArgs is an autogenerated conversion function.
+func Convert_v1beta2_MetricsAggregationArgs_To_config_MetricsAggregationArgs(in *MetricsAggregationArgs, out *config.MetricsAggregationArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_MetricsAggregationArgs_To_config_MetricsAggregationArgs(in, out, s)
+}
+
+func autoConvert_config_MetricsAggregationArgs_To_v1beta2_MetricsAggregationArgs(in *config.MetricsAggregationArgs, out *MetricsAggregationArgs, s conversion.Scope) error {
+	out.Type = AggregationType(in.Type)
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.HalfLife, &out.HalfLife, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_MetricsAggregationArgs_To_v1beta2_MetricsAggregationArgs is an autogenerated conversion function.
+func Convert_config_MetricsAggregationArgs_To_v1beta2_MetricsAggregationArgs(in *config.MetricsAggregationArgs, out *MetricsAggregationArgs, s conversion.Scope) error {
+	return autoConvert_config_MetricsAggregationArgs_To_v1beta2_MetricsAggregationArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_MetricsProviderArgs_To_config_MetricsProviderArgs(in *MetricsProviderArgs, out *config.MetricsProviderArgs, s conversion.Scope) error {
+	out.Type = config.MetricsProviderType(in.Type)
+	if err := Convert_v1beta2_PrometheusArgs_To_config_PrometheusArgs(&in.Prometheus, &out.Prometheus, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_MetricsProviderArgs_To_config_MetricsProviderArgs is an autogenerated conversion function.
+func Convert_v1beta2_MetricsProviderArgs_To_config_MetricsProviderArgs(in *MetricsProviderArgs, out *config.MetricsProviderArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_MetricsProviderArgs_To_config_MetricsProviderArgs(in, out, s)
+}
+
+func autoConvert_config_MetricsProviderArgs_To_v1beta2_MetricsProviderArgs(in *config.MetricsProviderArgs, out *MetricsProviderArgs, s conversion.Scope) error {
+	out.Type = MetricsProviderType(in.Type)
+	if err := Convert_config_PrometheusArgs_To_v1beta2_PrometheusArgs(&in.Prometheus, &out.Prometheus, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_MetricsProviderArgs_To_v1beta2_MetricsProviderArgs is an autogenerated conversion function.
+func Convert_config_MetricsProviderArgs_To_v1beta2_MetricsProviderArgs(in *config.MetricsProviderArgs, out *MetricsProviderArgs, s conversion.Scope) error {
+	return autoConvert_config_MetricsProviderArgs_To_v1beta2_MetricsProviderArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_NodePressureArgs_To_config_NodePressureArgs(in *NodePressureArgs, out *config.NodePressureArgs, s conversion.Scope) error {
+	out.Policy = config.NodePressurePolicy(in.Policy)
+	if err := v1.Convert_Pointer_int64_To_int64(&in.Penalty, &out.Penalty, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.Cooldown, &out.Cooldown, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_NodePressureArgs_To_config_NodePressureArgs is an autogenerated conversion function.
+func Convert_v1beta2_NodePressureArgs_To_config_NodePressureArgs(in *NodePressureArgs, out *config.NodePressureArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_NodePressureArgs_To_config_NodePressureArgs(in, out, s)
+}
+
+func autoConvert_config_NodePressureArgs_To_v1beta2_NodePressureArgs(in *config.NodePressureArgs, out *NodePressureArgs, s conversion.Scope) error {
+	out.Policy = NodePressurePolicy(in.Policy)
+	if err := v1.Convert_int64_To_Pointer_int64(&in.Penalty, &out.Penalty, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.Cooldown, &out.Cooldown, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_NodePressureArgs_To_v1beta2_NodePressureArgs is an autogenerated conversion function.
+func Convert_config_NodePressureArgs_To_v1beta2_NodePressureArgs(in *config.NodePressureArgs, out *NodePressureArgs, s conversion.Scope) error {
+	return autoConvert_config_NodePressureArgs_To_v1beta2_NodePressureArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_NodeRankingArgs_To_config_NodeRankingArgs(in *NodeRankingArgs, out *config.NodeRankingArgs, s conversion.Scope) error {
+	out.Direction = config.RankingDirection(in.Direction)
+	out.Rules = *(*[]config.RankingRule)(unsafe.Pointer(&in.Rules))
+	if err := v1.Convert_Pointer_int64_To_int64(&in.Weight, &out.Weight, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_NodeRankingArgs_To_config_NodeRankingArgs is an autogenerated conversion function.
+func Convert_v1beta2_NodeRankingArgs_To_config_NodeRankingArgs(in *NodeRankingArgs, out *config.NodeRankingArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_NodeRankingArgs_To_config_NodeRankingArgs(in, out, s)
+}
+
+func autoConvert_config_NodeRankingArgs_To_v1beta2_NodeRankingArgs(in *config.NodeRankingArgs, out *NodeRankingArgs, s conversion.Scope) error {
+	out.Direction = RankingDirection(in.Direction)
+	out.Rules = *(*[]RankingRule)(unsafe.Pointer(&in.Rules))
+	if err := v1.Convert_int64_To_Pointer_int64(&in.Weight, &out.Weight, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_NodeRankingArgs_To_v1beta2_NodeRankingArgs is an autogenerated conversion function.
+func Convert_config_NodeRankingArgs_To_v1beta2_NodeRankingArgs(in *config.NodeRankingArgs, out *NodeRankingArgs, s conversion.Scope) error {
+	return autoConvert_config_NodeRankingArgs_To_v1beta2_NodeRankingArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_NodeToleranceProfile_To_config_NodeToleranceProfile(in *NodeToleranceProfile, out *config.NodeToleranceProfile, s conversion.Scope) error {
+	out.NodeSelector = (*v1.LabelSelector)(unsafe.Pointer(in.NodeSelector))
+	out.ToleranceCPURate = (*float64)(unsafe.Pointer(in.ToleranceCPURate))
+	out.ToleranceMemoryRate = (*float64)(unsafe.Pointer(in.ToleranceMemoryRate))
+	return nil
+}
+
+// Convert_v1beta2_NodeToleranceProfile_To_config_NodeToleranceProfile is an autogenerated conversion function.
+func Convert_v1beta2_NodeToleranceProfile_To_config_NodeToleranceProfile(in *NodeToleranceProfile, out *config.NodeToleranceProfile, s conversion.Scope) error {
+	return autoConvert_v1beta2_NodeToleranceProfile_To_config_NodeToleranceProfile(in, out, s)
+}
+
+func autoConvert_config_NodeToleranceProfile_To_v1beta2_NodeToleranceProfile(in *config.NodeToleranceProfile, out *NodeToleranceProfile, s conversion.Scope) error {
+	out.NodeSelector = (*v1.LabelSelector)(unsafe.Pointer(in.NodeSelector))
+	out.ToleranceCPURate = (*float64)(unsafe.Pointer(in.ToleranceCPURate))
+	out.ToleranceMemoryRate = (*float64)(unsafe.Pointer(in.ToleranceMemoryRate))
+	return nil
+}
+
+// Convert_config_NodeToleranceProfile_To_v1beta2_NodeToleranceProfile is an autogenerated conversion function.
+func Convert_config_NodeToleranceProfile_To_v1beta2_NodeToleranceProfile(in *config.NodeToleranceProfile, out *NodeToleranceProfile, s conversion.Scope) error {
+	return autoConvert_config_NodeToleranceProfile_To_v1beta2_NodeToleranceProfile(in, out, s)
+}
+
+func autoConvert_v1beta2_PodUsageEstimationArgs_To_config_PodUsageEstimationArgs(in *PodUsageEstimationArgs, out *config.PodUsageEstimationArgs, s conversion.Scope) error {
+	out.Estimator = config.PodUsageEstimatorType(in.Estimator)
+	if err := v1.Convert_Pointer_float64_To_float64(&in.UsageRequestRatio, &out.UsageRequestRatio, s); err != nil {
+		return err
+	}
+	out.HistoryStatistic = config.HistoryStatistic(in.HistoryStatistic)
+	return nil
+}
+
+// Convert_v1beta2_PodUsageEstimationArgs_To_config_PodUsageEstimationArgs is an autogenerated conversion function.
+func Convert_v1beta2_PodUsageEstimationArgs_To_config_PodUsageEstimationArgs(in *PodUsageEstimationArgs, out *config.PodUsageEstimationArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_PodUsageEstimationArgs_To_config_PodUsageEstimationArgs(in, out, s)
+}
+
+func autoConvert_config_PodUsageEstimationArgs_To_v1beta2_PodUsageEstimationArgs(in *config.PodUsageEstimationArgs, out *PodUsageEstimationArgs, s conversion.Scope) error {
+	out.Estimator = PodUsageEstimatorType(in.Estimator)
+	if err := v1.Convert_float64_To_Pointer_float64(&in.UsageRequestRatio, &out.UsageRequestRatio, s); err != nil {
+		return err
+	}
+	out.HistoryStatistic = HistoryStatistic(in.HistoryStatistic)
+	return nil
+}
+
+// Convert_config_PodUsageEstimationArgs_To_v1beta2_PodUsageEstimationArgs is an autogenerated conversion function.
+func Convert_config_PodUsageEstimationArgs_To_v1beta2_PodUsageEstimationArgs(in *config.PodUsageEstimationArgs, out *PodUsageEstimationArgs, s conversion.Scope) error {
+	return autoConvert_config_PodUsageEstimationArgs_To_v1beta2_PodUsageEstimationArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_PrometheusArgs_To_config_PrometheusArgs(in *PrometheusArgs, out *config.PrometheusArgs, s conversion.Scope) error {
+	out.Address = in.Address
+	out.CPUQuery = in.CPUQuery
+	out.MemoryQuery = in.MemoryQuery
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.Timeout, &out.Timeout, s); err != nil {
+		return err
+	}
+	out.ResourceQueries = *(*[]config.PrometheusResourceQuery)(unsafe.Pointer(&in.ResourceQueries))
+	if err := v1.Convert_Pointer_string_To_string(&in.TimestampQuery, &out.TimestampQuery, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_PrometheusArgs_To_config_PrometheusArgs is an autogenerated conversion function.
+func Convert_v1beta2_PrometheusArgs_To_config_PrometheusArgs(in *PrometheusArgs, out *config.PrometheusArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_PrometheusArgs_To_config_PrometheusArgs(in, out, s)
+}
+
+func autoConvert_config_PrometheusArgs_To_v1beta2_PrometheusArgs(in *config.PrometheusArgs, out *PrometheusArgs, s conversion.Scope) error {
+	out.Address = in.Address
+	out.CPUQuery = in.CPUQuery
+	out.MemoryQuery = in.MemoryQuery
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.Timeout, &out.Timeout, s); err != nil {
+		return err
+	}
+	out.ResourceQueries = *(*[]PrometheusResourceQuery)(unsafe.Pointer(&in.ResourceQueries))
+	if err := v1.Convert_string_To_Pointer_string(&in.TimestampQuery, &out.TimestampQuery, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_PrometheusArgs_To_v1beta2_PrometheusArgs is an autogenerated conversion function.
+func Convert_config_PrometheusArgs_To_v1beta2_PrometheusArgs(in *config.PrometheusArgs, out *PrometheusArgs, s conversion.Scope) error {
+	return autoConvert_config_PrometheusArgs_To_v1beta2_PrometheusArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_PrometheusResourceQuery_To_config_PrometheusResourceQuery(in *PrometheusResourceQuery, out *config.PrometheusResourceQuery, s conversion.Scope) error {
+	out.Name = corev1.ResourceName(in.Name)
+	out.Query = in.Query
+	return nil
+}
+
+// Convert_v1beta2_PrometheusResourceQuery_To_config_PrometheusResourceQuery is an autogenerated conversion function.
+func Convert_v1beta2_PrometheusResourceQuery_To_config_PrometheusResourceQuery(in *PrometheusResourceQuery, out *config.PrometheusResourceQuery, s conversion.Scope) error {
+	return autoConvert_v1beta2_PrometheusResourceQuery_To_config_PrometheusResourceQuery(in, out, s)
+}
+
+func autoConvert_config_PrometheusResourceQuery_To_v1beta2_PrometheusResourceQuery(in *config.PrometheusResourceQuery, out *PrometheusResourceQuery, s conversion.Scope) error {
+	out.Name = corev1.ResourceName(in.Name)
+	out.Query = in.Query
+	return nil
+}
+
+// Convert_config_PrometheusResourceQuery_To_v1beta2_PrometheusResourceQuery is an autogenerated conversion function.
+func Convert_config_PrometheusResourceQuery_To_v1beta2_PrometheusResourceQuery(in *config.PrometheusResourceQuery, out *PrometheusResourceQuery, s conversion.Scope) error {
+	return autoConvert_config_PrometheusResourceQuery_To_v1beta2_PrometheusResourceQuery(in, out, s)
+}
+
+func autoConvert_v1beta2_RankingRule_To_config_RankingRule(in *RankingRule, out *config.RankingRule, s conversion.Scope) error {
+	out.Dimension = config.RankingDimension(in.Dimension)
+	out.Epsilon = in.Epsilon
+	return nil
+}
+
+// Convert_v1beta2_RankingRule_To_config_RankingRule is an autogenerated conversion function.
+func Convert_v1beta2_RankingRule_To_config_RankingRule(in *RankingRule, out *config.RankingRule, s conversion.Scope) error {
+	return autoConvert_v1beta2_RankingRule_To_config_RankingRule(in, out, s)
+}
+
+func autoConvert_config_RankingRule_To_v1beta2_RankingRule(in *config.RankingRule, out *RankingRule, s conversion.Scope) error {
+	out.Dimension = RankingDimension(in.Dimension)
+	out.Epsilon = in.Epsilon
+	return nil
+}
+
+// Convert_config_RankingRule_To_v1beta2_RankingRule is an autogenerated conversion function.
+func Convert_config_RankingRule_To_v1beta2_RankingRule(in *config.RankingRule, out *RankingRule, s conversion.Scope) error {
+	return autoConvert_config_RankingRule_To_v1beta2_RankingRule(in, out, s)
+}
+
+func autoConvert_v1beta2_ResourceTolerance_To_config_ResourceTolerance(in *ResourceTolerance, out *config.ResourceTolerance, s conversion.Scope) error {
+	out.Name = corev1.ResourceName(in.Name)
+	out.ToleranceRate = (*float64)(unsafe.Pointer(in.ToleranceRate))
+	out.RequestToleranceRate = (*float64)(unsafe.Pointer(in.RequestToleranceRate))
+	return nil
+}
+
+// Convert_v1beta2_ResourceTolerance_To_config_ResourceTolerance is an autogenerated conversion function.
+func Convert_v1beta2_ResourceTolerance_To_config_ResourceTolerance(in *ResourceTolerance, out *config.ResourceTolerance, s conversion.Scope) error {
+	return autoConvert_v1beta2_ResourceTolerance_To_config_ResourceTolerance(in, out, s)
+}
+
+func autoConvert_config_ResourceTolerance_To_v1beta2_ResourceTolerance(in *config.ResourceTolerance, out *ResourceTolerance, s conversion.Scope) error {
+	out.Name = corev1.ResourceName(in.Name)
+	out.ToleranceRate = (*float64)(unsafe.Pointer(in.ToleranceRate))
+	out.RequestToleranceRate = (*float64)(unsafe.Pointer(in.RequestToleranceRate))
+	return nil
+}
+
+// Convert_config_ResourceTolerance_To_v1beta2_ResourceTolerance is an autogenerated conversion function.
+func Convert_config_ResourceTolerance_To_v1beta2_ResourceTolerance(in *config.ResourceTolerance, out *ResourceTolerance, s conversion.Scope) error {
+	return autoConvert_config_ResourceTolerance_To_v1beta2_ResourceTolerance(in, out, s)
+}
+
+func autoConvert_v1beta2_ScrapeArgs_To_config_ScrapeArgs(in *ScrapeArgs, out *config.ScrapeArgs, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.Interval, &out.Interval, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int64_To_int64(&in.PageSize, &out.PageSize, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_int32_To_int32(&in.Concurrency, &out.Concurrency, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_float64_To_float64(&in.QPS, &out.QPS, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta2_ScrapeArgs_To_config_ScrapeArgs is an autogenerated conversion function.
+func Convert_v1beta2_ScrapeArgs_To_config_ScrapeArgs(in *ScrapeArgs, out *config.ScrapeArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_ScrapeArgs_To_config_ScrapeArgs(in, out, s)
+}
+
+func autoConvert_config_ScrapeArgs_To_v1beta2_ScrapeArgs(in *config.ScrapeArgs, out *ScrapeArgs, s conversion.Scope) error {
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.Interval, &out.Interval, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int64_To_Pointer_int64(&in.PageSize, &out.PageSize, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_int32_To_Pointer_int32(&in.Concurrency, &out.Concurrency, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_float64_To_Pointer_float64(&in.QPS, &out.QPS, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_ScrapeArgs_To_v1beta2_ScrapeArgs is an autogenerated conversion function.
+func Convert_config_ScrapeArgs_To_v1beta2_ScrapeArgs(in *config.ScrapeArgs, out *ScrapeArgs, s conversion.Scope) error {
+	return autoConvert_config_ScrapeArgs_To_v1beta2_ScrapeArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_StalenessArgs_To_config_StalenessArgs(in *StalenessArgs, out *config.StalenessArgs, s conversion.Scope) error {
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.MaxAge, &out.MaxAge, s); err != nil {
+		return err
+	}
+	out.Policy = config.StaleMetricsPolicy(in.Policy)
+	return nil
+}
+
+// Convert_v1beta2_StalenessArgs_To_config_StalenessArgs is an autogenerated conversion function.
+func Convert_v1beta2_StalenessArgs_To_config_StalenessArgs(in *StalenessArgs, out *config.StalenessArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_StalenessArgs_To_config_StalenessArgs(in, out, s)
+}
+
+func autoConvert_config_StalenessArgs_To_v1beta2_StalenessArgs(in *config.StalenessArgs, out *StalenessArgs, s conversion.Scope) error {
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.MaxAge, &out.MaxAge, s); err != nil {
+		return err
+	}
+	out.Policy = StaleMetricsPolicy(in.Policy)
+	return nil
+}
+
+// Convert_config_StalenessArgs_To_v1beta2_StalenessArgs is an autogenerated conversion function.
+func Convert_config_StalenessArgs_To_v1beta2_StalenessArgs(in *config.StalenessArgs, out *StalenessArgs, s conversion.Scope) error {
+	return autoConvert_config_StalenessArgs_To_v1beta2_StalenessArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_ToleranceOverride_To_config_ToleranceOverride(in *ToleranceOverride, out *config.ToleranceOverride, s conversion.Scope) error {
+	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
+	out.LabelSelector = (*v1.LabelSelector)(unsafe.Pointer(in.LabelSelector))
+	out.PriorityClassNames = *(*[]string)(unsafe.Pointer(&in.PriorityClassNames))
+	out.QOSClasses = *(*[]corev1.PodQOSClass)(unsafe.Pointer(&in.QOSClasses))
+	out.ToleranceCPURate = (*float64)(unsafe.Pointer(in.ToleranceCPURate))
+	out.ToleranceMemoryRate = (*float64)(unsafe.Pointer(in.ToleranceMemoryRate))
+	return nil
+}
+
+// Convert_v1beta2_ToleranceOverride_To_config_ToleranceOverride is an autogenerated conversion function.
+func Convert_v1beta2_ToleranceOverride_To_config_ToleranceOverride(in *ToleranceOverride, out *config.ToleranceOverride, s conversion.Scope) error {
+	return autoConvert_v1beta2_ToleranceOverride_To_config_ToleranceOverride(in, out, s)
+}
+
+func autoConvert_config_ToleranceOverride_To_v1beta2_ToleranceOverride(in *config.ToleranceOverride, out *ToleranceOverride, s conversion.Scope) error {
+	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
+	out.LabelSelector = (*v1.LabelSelector)(unsafe.Pointer(in.LabelSelector))
+	out.PriorityClassNames = *(*[]string)(unsafe.Pointer(&in.PriorityClassNames))
+	out.QOSClasses = *(*[]corev1.PodQOSClass)(unsafe.Pointer(&in.QOSClasses))
+	out.ToleranceCPURate = (*float64)(unsafe.Pointer(in.ToleranceCPURate))
+	out.ToleranceMemoryRate = (*float64)(unsafe.Pointer(in.ToleranceMemoryRate))
+	return nil
+}
+
+// Convert_config_ToleranceOverride_To_v1beta2_ToleranceOverride is an autogenerated conversion function.
+func Convert_config_ToleranceOverride_To_v1beta2_ToleranceOverride(in *config.ToleranceOverride, out *ToleranceOverride, s conversion.Scope) error {
+	return autoConvert_config_ToleranceOverride_To_v1beta2_ToleranceOverride(in, out, s)
+}
@@ -1,15 +1,155 @@
 package v1beta3
 
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
 var (
 	DefaultToleranceCPURate    float64 = 80
 	DefaultToleranceMemoryRate float64 = 80
+	DefaultCPUWeight           int64   = 1
+	DefaultMemoryWeight        int64   = 1
+	DefaultRankingWeight       int64   = 1
+	DefaultRankingDirection            = SpreadRanking
+	DefaultRankingRules                = []RankingRule{
+		{Dimension: RealMemoryRanking, Epsilon: 5},
+		{Dimension: RealCPURanking, Epsilon: 5},
+		{Dimension: RequestMemoryRanking, Epsilon: 5},
+		{Dimension: RequestCPURanking, Epsilon: 5},
+	}
+	DefaultAggregationType                     = LatestAggregation
+	DefaultAggregationHalfLife                 = 5 * time.Minute
+	DefaultMetricsProviderType                 = MetricsServerProvider
+	DefaultPrometheusCPUQuery                  = `sum(rate(node_cpu_seconds_total{mode!="idle",node="$node"}[1m]))`
+	DefaultPrometheusMemoryQuery               = `node_memory_MemTotal_bytes{node="$node"} - node_memory_MemAvailable_bytes{node="$node"}`
+	DefaultPrometheusTimestampQuery            = `max(timestamp(node_memory_MemAvailable_bytes{node="$node"}))`
+	DefaultPrometheusTimeout                   = 10 * time.Second
+	DefaultScrapeInterval                      = time.Minute
+	DefaultScrapePageSize              int64   = 500
+	DefaultScrapeConcurrency           int32   = 10
+	DefaultScrapeQPS                   float64 = 50
+	DefaultMetricsMaxAge                       = 5 * time.Minute
+	DefaultStaleMetricsPolicy                  = RejectStaleMetrics
+	DefaultNodeAnnotationOverrides             = false
+	DefaultOvercommitRatio             float64 = 1
+	DefaultPodUsageEstimator                   = NoneEstimator
+	DefaultUsageRequestRatio           float64 = 1
+	DefaultHistoryStatistic                    = PeakHistoryStatistic
+	DefaultNodePressurePolicy                  = IgnoreNodePressure
+	DefaultNodePressurePenalty         int64   = 50
+	DefaultNodePressureCooldown                = 5 * time.Minute
+	DefaultEventInterval                       = 5 * time.Minute
+	DefaultMode                                = EnforceMode
+	DefaultDegradationPolicy                   = RequestBasedDegradation
+	DefaultDegradationFailureThreshold int32   = 3
+	DefaultLastKnownGoodTTL                    = 30 * time.Minute
+	DefaultCacheSyncTimeout                    = 2 * time.Minute
 )
 
 func SetDefaults_DynamicArgs(obj *DynamicArgs) {
-	if obj.ToleranceCPURate == 0 {
-		obj.ToleranceCPURate = DefaultToleranceCPURate
+	if obj.ToleranceCPURate == nil {
+		obj.ToleranceCPURate = pointer.Float64(DefaultToleranceCPURate)
+	}
+	if obj.ToleranceMemoryRate == nil {
+		obj.ToleranceMemoryRate = pointer.Float64(DefaultToleranceMemoryRate)
+	}
+	if obj.CPUWeight == nil {
+		obj.CPUWeight = pointer.Int64(DefaultCPUWeight)
+	}
+	if obj.MemoryWeight == nil {
+		obj.MemoryWeight = pointer.Int64(DefaultMemoryWeight)
+	}
+	if obj.Ranking.Direction == "" {
+		obj.Ranking.Direction = DefaultRankingDirection
+	}
+	if len(obj.Ranking.Rules) == 0 {
+		obj.Ranking.Rules = append([]RankingRule(nil), DefaultRankingRules...)
+	}
+	if obj.Ranking.Weight == nil {
+		obj.Ranking.Weight = pointer.Int64(DefaultRankingWeight)
+	}
+	if obj.Aggregation.Type == "" {
+		obj.Aggregation.Type = DefaultAggregationType
+	}
+	if obj.Aggregation.HalfLife == nil {
+		obj.Aggregation.HalfLife = &metav1.Duration{Duration: DefaultAggregationHalfLife}
+	}
+	if obj.MetricsProvider.Type == "" {
+		obj.MetricsProvider.Type = DefaultMetricsProviderType
+	}
+	if obj.MetricsProvider.Prometheus.CPUQuery == "" {
+		obj.MetricsProvider.Prometheus.CPUQuery = DefaultPrometheusCPUQuery
+	}
+	if obj.MetricsProvider.Prometheus.MemoryQuery == "" {
+		obj.MetricsProvider.Prometheus.MemoryQuery = DefaultPrometheusMemoryQuery
+	}
+	if obj.MetricsProvider.Prometheus.TimestampQuery == nil {
+		obj.MetricsProvider.Prometheus.TimestampQuery = pointer.String(DefaultPrometheusTimestampQuery)
+	}
+	if obj.MetricsProvider.Prometheus.Timeout == nil {
+		obj.MetricsProvider.Prometheus.Timeout = &metav1.Duration{Duration: DefaultPrometheusTimeout}
+	}
+	if obj.Scrape.Interval == nil {
+		obj.Scrape.Interval = &metav1.Duration{Duration: DefaultScrapeInterval}
+	}
+	if obj.Scrape.PageSize == nil {
+		obj.Scrape.PageSize = pointer.Int64(DefaultScrapePageSize)
+	}
+	if obj.Scrape.Concurrency == nil {
+		obj.Scrape.Concurrency = pointer.Int32(DefaultScrapeConcurrency)
+	}
+	if obj.Scrape.QPS == nil {
+		obj.Scrape.QPS = pointer.Float64(DefaultScrapeQPS)
+	}
+	if obj.Staleness.MaxAge == nil {
+		obj.Staleness.MaxAge = &metav1.Duration{Duration: DefaultMetricsMaxAge}
+	}
+	if obj.Staleness.Policy == "" {
+		obj.Staleness.Policy = DefaultStaleMetricsPolicy
+	}
+	if obj.NodeAnnotationOverrides == nil {
+		obj.NodeAnnotationOverrides = pointer.Bool(DefaultNodeAnnotationOverrides)
+	}
+	if obj.OvercommitRatio == nil {
+		obj.OvercommitRatio = pointer.Float64(DefaultOvercommitRatio)
+	}
+	if obj.PodUsageEstimation.Estimator == "" {
+		obj.PodUsageEstimation.Estimator = DefaultPodUsageEstimator
+	}
+	if obj.PodUsageEstimation.UsageRequestRatio == nil {
+		obj.PodUsageEstimation.UsageRequestRatio = pointer.Float64(DefaultUsageRequestRatio)
+	}
+	if obj.PodUsageEstimation.HistoryStatistic == "" {
+		obj.PodUsageEstimation.HistoryStatistic = DefaultHistoryStatistic
+	}
+	if obj.NodePressure.Policy == "" {
+		obj.NodePressure.Policy = DefaultNodePressurePolicy
+	}
+	if obj.NodePressure.Penalty == nil {
+		obj.NodePressure.Penalty = pointer.Int64(DefaultNodePressurePenalty)
+	}
+	if obj.NodePressure.Cooldown == nil {
+		obj.NodePressure.Cooldown = &metav1.Duration{Duration: DefaultNodePressureCooldown}
+	}
+	if obj.Events.Interval == nil {
+		obj.Events.Interval = &metav1.Duration{Duration: DefaultEventInterval}
+	}
+	if obj.Mode == "" {
+		obj.Mode = DefaultMode
+	}
+	if obj.Degradation.Policy == "" {
+		obj.Degradation.Policy = DefaultDegradationPolicy
+	}
+	if obj.Degradation.FailureThreshold == nil {
+		obj.Degradation.FailureThreshold = pointer.Int32(DefaultDegradationFailureThreshold)
+	}
+	if obj.Degradation.LastKnownGoodTTL == nil {
+		obj.Degradation.LastKnownGoodTTL = &metav1.Duration{Duration: DefaultLastKnownGoodTTL}
 	}
-	if obj.ToleranceMemoryRate == 0 {
-		obj.ToleranceMemoryRate = DefaultToleranceMemoryRate
+	if obj.CacheSyncTimeout == nil {
+		obj.CacheSyncTimeout = &metav1.Duration{Duration: DefaultCacheSyncTimeout}
 	}
 }
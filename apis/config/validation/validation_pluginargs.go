@@ -0,0 +1,370 @@
+package validation
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
+)
+
+var (
+	validRankingDimensions = sets.NewString(
+		string(config.RealCPURanking),
+		string(config.RealMemoryRanking),
+		string(config.RequestCPURanking),
+		string(config.RequestMemoryRanking),
+	)
+	validRankingDirections = sets.NewString(
+		string(config.SpreadRanking),
+		string(config.BinPackRanking),
+	)
+	validAggregationTypes = sets.NewString(
+		string(config.LatestAggregation),
+		string(config.MeanAggregation),
+		string(config.MaxAggregation),
+		string(config.P90Aggregation),
+		string(config.P95Aggregation),
+		string(config.EWMAAggregation),
+	)
+	validMetricsProviderTypes = sets.NewString(
+		string(config.MetricsServerProvider),
+		string(config.PrometheusProvider),
+	)
+	validStaleMetricsPolicies = sets.NewString(
+		string(config.RejectStaleMetrics),
+		string(config.AdmitStaleMetrics),
+		string(config.RequestBasedStaleMetrics),
+	)
+	validPodUsageEstimators = sets.NewString(
+		string(config.NoneEstimator),
+		string(config.RequestEstimator),
+		string(config.RatioEstimator),
+		string(config.HistoryEstimator),
+	)
+	validHistoryStatistics = sets.NewString(
+		string(config.MeanHistoryStatistic),
+		string(config.PeakHistoryStatistic),
+	)
+	validNodePressurePolicies = sets.NewString(
+		string(config.IgnoreNodePressure),
+		string(config.RejectNodePressure),
+		string(config.PenalizeNodePressure),
+	)
+	validModes = sets.NewString(
+		string(config.EnforceMode),
+		string(config.ShadowMode),
+	)
+	validDegradationPolicies = sets.NewString(
+		string(config.PassThroughDegradation),
+		string(config.RequestBasedDegradation),
+		string(config.LastKnownGoodDegradation),
+	)
+	validQOSClasses = sets.NewString(
+		string(corev1.PodQOSGuaranteed),
+		string(corev1.PodQOSBurstable),
+		string(corev1.PodQOSBestEffort),
+	)
+)
+
+// ValidateDynamicArgs validates that DynamicArgs are correct.
+func ValidateDynamicArgs(path *field.Path, args *config.DynamicArgs) field.ErrorList {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validateRate(path.Child("toleranceCPURate"), args.ToleranceCPURate)...)
+	allErrs = append(allErrs, validateRate(path.Child("toleranceMemoryRate"), args.ToleranceMemoryRate)...)
+	allErrs = append(allErrs, validateNonNegative(path.Child("cpuWeight"), args.CPUWeight)...)
+	allErrs = append(allErrs, validateNonNegative(path.Child("memoryWeight"), args.MemoryWeight)...)
+	allErrs = append(allErrs, validateNodeRankingArgs(path.Child("ranking"), &args.Ranking)...)
+	allErrs = append(allErrs, validateMetricsAggregationArgs(path.Child("aggregation"), &args.Aggregation)...)
+	allErrs = append(allErrs, validateMetricsProviderArgs(path.Child("metricsProvider"), &args.MetricsProvider)...)
+	allErrs = append(allErrs, validateScrapeArgs(path.Child("scrape"), &args.Scrape)...)
+	allErrs = append(allErrs, validateStalenessArgs(path.Child("staleness"), &args.Staleness)...)
+	if args.RequestToleranceCPURate != nil {
+		allErrs = append(allErrs, validateRate(path.Child("requestToleranceCPURate"), *args.RequestToleranceCPURate)...)
+	}
+	if args.RequestToleranceMemoryRate != nil {
+		allErrs = append(allErrs, validateRate(path.Child("requestToleranceMemoryRate"), *args.RequestToleranceMemoryRate)...)
+	}
+	if args.OvercommitRatio <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("overcommitRatio"), args.OvercommitRatio, "must be greater than 0"))
+	}
+	allErrs = append(allErrs, validatePodUsageEstimationArgs(path.Child("podUsageEstimation"), &args.PodUsageEstimation)...)
+	// Only the metrics server provider reports the usage of pods.
+	if args.PodUsageEstimation.Estimator == config.HistoryEstimator && args.MetricsProvider.Type != config.MetricsServerProvider {
+		allErrs = append(allErrs, field.Invalid(path.Child("podUsageEstimation", "estimator"), args.PodUsageEstimation.Estimator,
+			fmt.Sprintf("requires the %v metrics provider", config.MetricsServerProvider)))
+	}
+	allErrs = append(allErrs, validateResourceTolerances(path.Child("resources"), args.Resources)...)
+	allErrs = append(allErrs, validateNodePressureArgs(path.Child("nodePressure"), &args.NodePressure)...)
+	if args.Events.Interval.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("events", "interval"), args.Events.Interval.Duration.String(), "must not be negative"))
+	}
+	if !validModes.Has(string(args.Mode)) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("mode"), args.Mode, validModes.List()))
+	}
+	allErrs = append(allErrs, validateDegradationArgs(path.Child("degradation"), &args.Degradation)...)
+	allErrs = append(allErrs, validatePositiveDuration(path.Child("cacheSyncTimeout"), args.CacheSyncTimeout)...)
+	if args.Debug.BindAddress != "" {
+		if _, _, err := net.SplitHostPort(args.Debug.BindAddress); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("debug", "bindAddress"), args.Debug.BindAddress, err.Error()))
+		}
+	}
+	if args.Debug.HealthBindAddress != "" {
+		if _, _, err := net.SplitHostPort(args.Debug.HealthBindAddress); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("debug", "healthBindAddress"), args.Debug.HealthBindAddress, err.Error()))
+		} else if args.Debug.HealthBindAddress == args.Debug.BindAddress {
+			allErrs = append(allErrs, field.Invalid(path.Child("debug", "healthBindAddress"), args.Debug.HealthBindAddress, "must differ from debug.bindAddress"))
+		}
+	}
+	for i := range args.ToleranceOverrides {
+		allErrs = append(allErrs, validateToleranceOverride(path.Child("toleranceOverrides").Index(i), &args.ToleranceOverrides[i])...)
+	}
+	for i := range args.NodeProfiles {
+		allErrs = append(allErrs, validateNodeToleranceProfile(path.Child("nodeProfiles").Index(i), &args.NodeProfiles[i])...)
+	}
+
+	return allErrs
+}
+
+func validateNodeRankingArgs(path *field.Path, args *config.NodeRankingArgs) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if !validRankingDirections.Has(string(args.Direction)) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("direction"), args.Direction, validRankingDirections.List()))
+	}
+
+	seen := sets.NewString()
+	for i, rule := range args.Rules {
+		rulePath := path.Child("rules").Index(i)
+		if !validRankingDimensions.Has(string(rule.Dimension)) {
+			allErrs = append(allErrs, field.NotSupported(rulePath.Child("dimension"), rule.Dimension, validRankingDimensions.List()))
+		} else if seen.Has(string(rule.Dimension)) {
+			allErrs = append(allErrs, field.Duplicate(rulePath.Child("dimension"), rule.Dimension))
+		}
+		seen.Insert(string(rule.Dimension))
+
+		if rule.Epsilon < 0 || rule.Epsilon > 100 {
+			allErrs = append(allErrs, field.Invalid(rulePath.Child("epsilon"), rule.Epsilon, "must be in the range [0, 100]"))
+		}
+	}
+
+	allErrs = append(allErrs, validateNonNegative(path.Child("weight"), args.Weight)...)
+	return allErrs
+}
+
+func validateMetricsAggregationArgs(path *field.Path, args *config.MetricsAggregationArgs) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if !validAggregationTypes.Has(string(args.Type)) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("type"), args.Type, validAggregationTypes.List()))
+	}
+	if args.Type == config.EWMAAggregation {
+		allErrs = append(allErrs, validatePositiveDuration(path.Child("halfLife"), args.HalfLife)...)
+	}
+	return allErrs
+}
+
+func validateMetricsProviderArgs(path *field.Path, args *config.MetricsProviderArgs) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if !validMetricsProviderTypes.Has(string(args.Type)) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("type"), args.Type, validMetricsProviderTypes.List()))
+	}
+	if args.Type != config.PrometheusProvider {
+		return allErrs
+	}
+
+	promPath := path.Child("prometheus")
+	if args.Prometheus.Address == "" {
+		allErrs = append(allErrs, field.Required(promPath.Child("address"), "must be set for the Prometheus metrics provider"))
+	} else if u, err := url.Parse(args.Prometheus.Address); err != nil || u.Scheme == "" || u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(promPath.Child("address"), args.Prometheus.Address, "must be an absolute URL"))
+	}
+	if args.Prometheus.CPUQuery == "" {
+		allErrs = append(allErrs, field.Required(promPath.Child("cpuQuery"), ""))
+	}
+	if args.Prometheus.MemoryQuery == "" {
+		allErrs = append(allErrs, field.Required(promPath.Child("memoryQuery"), ""))
+	}
+	allErrs = append(allErrs, validatePositiveDuration(promPath.Child("timeout"), args.Prometheus.Timeout)...)
+	for i, q := range args.Prometheus.ResourceQueries {
+		queryPath := promPath.Child("resourceQueries").Index(i)
+		allErrs = append(allErrs, validateExtraResourceName(queryPath.Child("name"), q.Name)...)
+		if q.Query == "" {
+			allErrs = append(allErrs, field.Required(queryPath.Child("query"), ""))
+		}
+	}
+	return allErrs
+}
+
+func validateScrapeArgs(path *field.Path, args *config.ScrapeArgs) field.ErrorList {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validatePositiveDuration(path.Child("interval"), args.Interval)...)
+	if args.PageSize <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("pageSize"), args.PageSize, "must be greater than 0"))
+	}
+	if args.Concurrency <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("concurrency"), args.Concurrency, "must be greater than 0"))
+	}
+	if args.QPS <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("qps"), args.QPS, "must be greater than 0"))
+	}
+	return allErrs
+}
+
+func validateStalenessArgs(path *field.Path, args *config.StalenessArgs) field.ErrorList {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validatePositiveDuration(path.Child("maxAge"), args.MaxAge)...)
+	if !validStaleMetricsPolicies.Has(string(args.Policy)) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("policy"), args.Policy, validStaleMetricsPolicies.List()))
+	}
+	return allErrs
+}
+
+func validatePodUsageEstimationArgs(path *field.Path, args *config.PodUsageEstimationArgs) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if !validPodUsageEstimators.Has(string(args.Estimator)) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("estimator"), args.Estimator, validPodUsageEstimators.List()))
+	}
+	if args.UsageRequestRatio <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("usageRequestRatio"), args.UsageRequestRatio, "must be greater than 0"))
+	}
+	if !validHistoryStatistics.Has(string(args.HistoryStatistic)) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("historyStatistic"), args.HistoryStatistic, validHistoryStatistics.List()))
+	}
+	return allErrs
+}
+
+func validateNodePressureArgs(path *field.Path, args *config.NodePressureArgs) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if !validNodePressurePolicies.Has(string(args.Policy)) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("policy"), args.Policy, validNodePressurePolicies.List()))
+	}
+	if args.Penalty < 0 || args.Penalty > 100 {
+		allErrs = append(allErrs, field.Invalid(path.Child("penalty"), args.Penalty, "must be in the range [0, 100]"))
+	}
+	if args.Cooldown.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("cooldown"), args.Cooldown.Duration.String(), "must not be negative"))
+	}
+	return allErrs
+}
+
+func validateDegradationArgs(path *field.Path, args *config.DegradationArgs) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if !validDegradationPolicies.Has(string(args.Policy)) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("policy"), args.Policy, validDegradationPolicies.List()))
+	}
+	if args.FailureThreshold <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("failureThreshold"), args.FailureThreshold, "must be greater than 0"))
+	}
+	allErrs = append(allErrs, validatePositiveDuration(path.Child("lastKnownGoodTTL"), args.LastKnownGoodTTL)...)
+	return allErrs
+}
+
+func validateResourceTolerances(path *field.Path, resources []config.ResourceTolerance) field.ErrorList {
+	var allErrs field.ErrorList
+
+	seen := sets.NewString()
+	for i, r := range resources {
+		resourcePath := path.Index(i)
+		allErrs = append(allErrs, validateExtraResourceName(resourcePath.Child("name"), r.Name)...)
+		if seen.Has(string(r.Name)) {
+			allErrs = append(allErrs, field.Duplicate(resourcePath.Child("name"), r.Name))
+		}
+		seen.Insert(string(r.Name))
+
+		if r.ToleranceRate != nil {
+			allErrs = append(allErrs, validateRate(resourcePath.Child("toleranceRate"), *r.ToleranceRate)...)
+		}
+		if r.RequestToleranceRate != nil {
+			allErrs = append(allErrs, validateRate(resourcePath.Child("requestToleranceRate"), *r.RequestToleranceRate)...)
+		}
+	}
+	return allErrs
+}
+
+// validateExtraResourceName validates the name of a resource other than
+// cpu and memory, which have their own fields.
+func validateExtraResourceName(path *field.Path, name corev1.ResourceName) field.ErrorList {
+	if name == corev1.ResourceCPU || name == corev1.ResourceMemory {
+		return field.ErrorList{field.Invalid(path, name, "cpu and memory have their own tolerances")}
+	}
+
+	var allErrs field.ErrorList
+	for _, msg := range utilvalidation.IsQualifiedName(string(name)) {
+		allErrs = append(allErrs, field.Invalid(path, name, msg))
+	}
+	return allErrs
+}
+
+func validateToleranceOverride(path *field.Path, o *config.ToleranceOverride) field.ErrorList {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, metav1validation.ValidateLabelSelector(o.LabelSelector,
+		metav1validation.LabelSelectorValidationOptions{}, path.Child("labelSelector"))...)
+	for i, c := range o.QOSClasses {
+		if !validQOSClasses.Has(string(c)) {
+			allErrs = append(allErrs, field.NotSupported(path.Child("qosClasses").Index(i), c, validQOSClasses.List()))
+		}
+	}
+	if o.ToleranceCPURate != nil {
+		allErrs = append(allErrs, validateRate(path.Child("toleranceCPURate"), *o.ToleranceCPURate)...)
+	}
+	if o.ToleranceMemoryRate != nil {
+		allErrs = append(allErrs, validateRate(path.Child("toleranceMemoryRate"), *o.ToleranceMemoryRate)...)
+	}
+	return allErrs
+}
+
+func validateNodeToleranceProfile(path *field.Path, p *config.NodeToleranceProfile) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if p.NodeSelector == nil {
+		allErrs = append(allErrs, field.Required(path.Child("nodeSelector"), ""))
+	} else {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(p.NodeSelector,
+			metav1validation.LabelSelectorValidationOptions{}, path.Child("nodeSelector"))...)
+	}
+	if p.ToleranceCPURate != nil {
+		allErrs = append(allErrs, validateRate(path.Child("toleranceCPURate"), *p.ToleranceCPURate)...)
+	}
+	if p.ToleranceMemoryRate != nil {
+		allErrs = append(allErrs, validateRate(path.Child("toleranceMemoryRate"), *p.ToleranceMemoryRate)...)
+	}
+	return allErrs
+}
+
+// validateRate validates a usage rate in percent.
+func validateRate(path *field.Path, rate float64) field.ErrorList {
+	if rate < 0 || rate > 100 {
+		return field.ErrorList{field.Invalid(path, rate, "must be in the range [0, 100]")}
+	}
+	return nil
+}
+
+func validateNonNegative(path *field.Path, value int64) field.ErrorList {
+	if value < 0 {
+		return field.ErrorList{field.Invalid(path, value, "must not be negative")}
+	}
+	return nil
+}
+
+func validatePositiveDuration(path *field.Path, d metav1.Duration) field.ErrorList {
+	if d.Duration <= 0 {
+		return field.ErrorList{field.Invalid(path, d.Duration.String(), "must be greater than 0")}
+	}
+	return nil
+}
@@ -0,0 +1,430 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+
+	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
+	"github.com/tanjunchen/tanjunchen-scheduler/apis/config/scheme"
+	v1 "github.com/tanjunchen/tanjunchen-scheduler/apis/config/v1"
+)
+
+// defaultArgs returns the DynamicArgs of an empty v1 configuration.
+func defaultArgs(t *testing.T) *config.DynamicArgs {
+	t.Helper()
+	versioned := &v1.DynamicArgs{}
+	scheme.Scheme.Default(versioned)
+	args := &config.DynamicArgs{}
+	if err := scheme.Scheme.Convert(versioned, args, nil); err != nil {
+		t.Fatalf("convert default DynamicArgs err: %v", err)
+	}
+	return args
+}
+
+func TestValidateDynamicArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(args *config.DynamicArgs)
+		// wantFields are the fields of the expected errors, in order.
+		wantFields []string
+	}{
+		{
+			name:   "defaults",
+			update: func(args *config.DynamicArgs) {},
+		},
+		{
+			name: "valid tolerances",
+			update: func(args *config.DynamicArgs) {
+				args.ToleranceCPURate = 0
+				args.ToleranceMemoryRate = 100
+			},
+		},
+		{
+			name: "tolerances out of range",
+			update: func(args *config.DynamicArgs) {
+				args.ToleranceCPURate = -1
+				args.ToleranceMemoryRate = 101
+			},
+			wantFields: []string{"toleranceCPURate", "toleranceMemoryRate"},
+		},
+		{
+			name: "valid weights",
+			update: func(args *config.DynamicArgs) {
+				args.CPUWeight = 0
+				args.MemoryWeight = 3
+			},
+		},
+		{
+			name: "negative weights",
+			update: func(args *config.DynamicArgs) {
+				args.CPUWeight = -1
+				args.MemoryWeight = -1
+			},
+			wantFields: []string{"cpuWeight", "memoryWeight"},
+		},
+		{
+			name: "valid ranking",
+			update: func(args *config.DynamicArgs) {
+				args.Ranking = config.NodeRankingArgs{
+					Direction: config.BinPackRanking,
+					Rules:     []config.RankingRule{{Dimension: config.RequestCPURanking, Epsilon: 0}},
+					Weight:    0,
+				}
+			},
+		},
+		{
+			name: "invalid ranking",
+			update: func(args *config.DynamicArgs) {
+				args.Ranking = config.NodeRankingArgs{
+					Direction: "Random",
+					Rules: []config.RankingRule{
+						{Dimension: config.RealCPURanking, Epsilon: 5},
+						{Dimension: config.RealCPURanking, Epsilon: 101},
+						{Dimension: "Disk", Epsilon: 5},
+					},
+					Weight: -1,
+				}
+			},
+			wantFields: []string{
+				"ranking.direction",
+				"ranking.rules[1].dimension",
+				"ranking.rules[1].epsilon",
+				"ranking.rules[2].dimension",
+				"ranking.weight",
+			},
+		},
+		{
+			name: "valid aggregation",
+			update: func(args *config.DynamicArgs) {
+				args.Aggregation = config.MetricsAggregationArgs{Type: config.EWMAAggregation, HalfLife: metav1.Duration{Duration: time.Minute}}
+			},
+		},
+		{
+			name: "invalid aggregation",
+			update: func(args *config.DynamicArgs) {
+				args.Aggregation = config.MetricsAggregationArgs{Type: "Median"}
+			},
+			wantFields: []string{"aggregation.type"},
+		},
+		{
+			name: "EWMA aggregation without half life",
+			update: func(args *config.DynamicArgs) {
+				args.Aggregation = config.MetricsAggregationArgs{Type: config.EWMAAggregation}
+			},
+			wantFields: []string{"aggregation.halfLife"},
+		},
+		{
+			name: "valid Prometheus provider",
+			update: func(args *config.DynamicArgs) {
+				args.MetricsProvider.Type = config.PrometheusProvider
+				args.MetricsProvider.Prometheus.Address = "http://prometheus:9090"
+				args.MetricsProvider.Prometheus.ResourceQueries = []config.PrometheusResourceQuery{
+					{Name: corev1.ResourceEphemeralStorage, Query: `node_filesystem_size_bytes{node="$node"}`},
+				}
+			},
+		},
+		{
+			name: "invalid metrics provider type",
+			update: func(args *config.DynamicArgs) {
+				args.MetricsProvider.Type = "Datadog"
+			},
+			wantFields: []string{"metricsProvider.type"},
+		},
+		{
+			name: "invalid Prometheus provider",
+			update: func(args *config.DynamicArgs) {
+				args.MetricsProvider.Type = config.PrometheusProvider
+				args.MetricsProvider.Prometheus = config.PrometheusArgs{
+					Address: "prometheus:9090",
+					ResourceQueries: []config.PrometheusResourceQuery{
+						{Name: corev1.ResourceCPU, Query: "cpu"},
+						{Name: corev1.ResourceEphemeralStorage},
+					},
+				}
+			},
+			wantFields: []string{
+				"metricsProvider.prometheus.address",
+				"metricsProvider.prometheus.cpuQuery",
+				"metricsProvider.prometheus.memoryQuery",
+				"metricsProvider.prometheus.timeout",
+				"metricsProvider.prometheus.resourceQueries[0].name",
+				"metricsProvider.prometheus.resourceQueries[1].query",
+			},
+		},
+		{
+			name: "valid scrape",
+			update: func(args *config.DynamicArgs) {
+				args.Scrape = config.ScrapeArgs{Interval: metav1.Duration{Duration: time.Second}, PageSize: 1, Concurrency: 1, QPS: 0.5}
+			},
+		},
+		{
+			name: "invalid scrape",
+			update: func(args *config.DynamicArgs) {
+				args.Scrape = config.ScrapeArgs{}
+			},
+			wantFields: []string{"scrape.interval", "scrape.pageSize", "scrape.concurrency", "scrape.qps"},
+		},
+		{
+			name: "valid staleness",
+			update: func(args *config.DynamicArgs) {
+				args.Staleness = config.StalenessArgs{MaxAge: metav1.Duration{Duration: time.Minute}, Policy: config.RequestBasedStaleMetrics}
+			},
+		},
+		{
+			name: "invalid staleness",
+			update: func(args *config.DynamicArgs) {
+				args.Staleness = config.StalenessArgs{Policy: "Ignore"}
+			},
+			wantFields: []string{"staleness.maxAge", "staleness.policy"},
+		},
+		{
+			name: "valid request tolerances",
+			update: func(args *config.DynamicArgs) {
+				args.RequestToleranceCPURate = pointer.Float64(0)
+				args.RequestToleranceMemoryRate = pointer.Float64(100)
+				args.OvercommitRatio = 1.5
+			},
+		},
+		{
+			name: "invalid request tolerances",
+			update: func(args *config.DynamicArgs) {
+				args.RequestToleranceCPURate = pointer.Float64(-1)
+				args.RequestToleranceMemoryRate = pointer.Float64(101)
+				args.OvercommitRatio = 0
+			},
+			wantFields: []string{"requestToleranceCPURate", "requestToleranceMemoryRate", "overcommitRatio"},
+		},
+		{
+			name: "valid pod usage estimation",
+			update: func(args *config.DynamicArgs) {
+				args.PodUsageEstimation = config.PodUsageEstimationArgs{
+					Estimator:         config.HistoryEstimator,
+					UsageRequestRatio: 0.5,
+					HistoryStatistic:  config.MeanHistoryStatistic,
+				}
+			},
+		},
+		{
+			name: "invalid pod usage estimation",
+			update: func(args *config.DynamicArgs) {
+				args.PodUsageEstimation = config.PodUsageEstimationArgs{Estimator: "Oracle", HistoryStatistic: "P50"}
+			},
+			wantFields: []string{
+				"podUsageEstimation.estimator",
+				"podUsageEstimation.usageRequestRatio",
+				"podUsageEstimation.historyStatistic",
+			},
+		},
+		{
+			name: "History estimator with Prometheus provider",
+			update: func(args *config.DynamicArgs) {
+				args.MetricsProvider.Type = config.PrometheusProvider
+				args.MetricsProvider.Prometheus.Address = "http://prometheus:9090"
+				args.PodUsageEstimation.Estimator = config.HistoryEstimator
+			},
+			wantFields: []string{"podUsageEstimation.estimator"},
+		},
+		{
+			name: "valid resources",
+			update: func(args *config.DynamicArgs) {
+				args.Resources = []config.ResourceTolerance{
+					{Name: corev1.ResourceEphemeralStorage, ToleranceRate: pointer.Float64(90)},
+					{Name: "nvidia.com/gpu", RequestToleranceRate: pointer.Float64(100)},
+				}
+			},
+		},
+		{
+			name: "invalid resources",
+			update: func(args *config.DynamicArgs) {
+				args.Resources = []config.ResourceTolerance{
+					{Name: corev1.ResourceMemory},
+					{Name: corev1.ResourceEphemeralStorage, ToleranceRate: pointer.Float64(-1)},
+					{Name: corev1.ResourceEphemeralStorage, RequestToleranceRate: pointer.Float64(101)},
+				}
+			},
+			wantFields: []string{
+				"resources[0].name",
+				"resources[1].toleranceRate",
+				"resources[2].name",
+				"resources[2].requestToleranceRate",
+			},
+		},
+		{
+			name: "valid node pressure",
+			update: func(args *config.DynamicArgs) {
+				args.NodePressure = config.NodePressureArgs{Policy: config.PenalizeNodePressure, Penalty: 100}
+			},
+		},
+		{
+			name: "invalid node pressure",
+			update: func(args *config.DynamicArgs) {
+				args.NodePressure = config.NodePressureArgs{Policy: "Evict", Penalty: 101, Cooldown: metav1.Duration{Duration: -time.Second}}
+			},
+			wantFields: []string{"nodePressure.policy", "nodePressure.penalty", "nodePressure.cooldown"},
+		},
+		{
+			name: "events every time",
+			update: func(args *config.DynamicArgs) {
+				args.Events.Interval.Duration = 0
+			},
+		},
+		{
+			name: "negative events interval",
+			update: func(args *config.DynamicArgs) {
+				args.Events.Interval.Duration = -time.Second
+			},
+			wantFields: []string{"events.interval"},
+		},
+		{
+			name: "shadow mode",
+			update: func(args *config.DynamicArgs) {
+				args.Mode = config.ShadowMode
+			},
+		},
+		{
+			name: "invalid mode",
+			update: func(args *config.DynamicArgs) {
+				args.Mode = "DryRun"
+			},
+			wantFields: []string{"mode"},
+		},
+		{
+			name: "valid degradation",
+			update: func(args *config.DynamicArgs) {
+				args.Degradation = config.DegradationArgs{
+					Policy:           config.LastKnownGoodDegradation,
+					FailureThreshold: 1,
+					LastKnownGoodTTL: metav1.Duration{Duration: time.Hour},
+				}
+			},
+		},
+		{
+			name: "invalid degradation",
+			update: func(args *config.DynamicArgs) {
+				args.Degradation = config.DegradationArgs{Policy: "Panic"}
+			},
+			wantFields: []string{"degradation.policy", "degradation.failureThreshold", "degradation.lastKnownGoodTTL"},
+		},
+		{
+			name: "invalid cache sync timeout",
+			update: func(args *config.DynamicArgs) {
+				args.CacheSyncTimeout.Duration = 0
+			},
+			wantFields: []string{"cacheSyncTimeout"},
+		},
+		{
+			name: "valid debug bind address",
+			update: func(args *config.DynamicArgs) {
+				args.Debug.BindAddress = ":10260"
+			},
+		},
+		{
+			name: "invalid debug bind address",
+			update: func(args *config.DynamicArgs) {
+				args.Debug.BindAddress = "localhost"
+			},
+			wantFields: []string{"debug.bindAddress"},
+		},
+		{
+			name: "valid health bind address",
+			update: func(args *config.DynamicArgs) {
+				args.Debug.BindAddress = "127.0.0.1:10260"
+				args.Debug.HealthBindAddress = ":10261"
+			},
+		},
+		{
+			name: "invalid health bind address",
+			update: func(args *config.DynamicArgs) {
+				args.Debug.HealthBindAddress = "localhost"
+			},
+			wantFields: []string{"debug.healthBindAddress"},
+		},
+		{
+			name: "health bind address of the debug endpoint",
+			update: func(args *config.DynamicArgs) {
+				args.Debug.BindAddress = "127.0.0.1:10260"
+				args.Debug.HealthBindAddress = "127.0.0.1:10260"
+			},
+			wantFields: []string{"debug.healthBindAddress"},
+		},
+		{
+			name: "valid tolerance override",
+			update: func(args *config.DynamicArgs) {
+				args.ToleranceOverrides = []config.ToleranceOverride{{
+					LabelSelector:    &metav1.LabelSelector{MatchLabels: map[string]string{"app": "batch"}},
+					QOSClasses:       []corev1.PodQOSClass{corev1.PodQOSBestEffort},
+					ToleranceCPURate: pointer.Float64(95),
+				}}
+			},
+		},
+		{
+			name: "invalid tolerance override",
+			update: func(args *config.DynamicArgs) {
+				args.ToleranceOverrides = []config.ToleranceOverride{{
+					LabelSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "batch job"}},
+					QOSClasses:          []corev1.PodQOSClass{"Premium"},
+					ToleranceCPURate:    pointer.Float64(-5),
+					ToleranceMemoryRate: pointer.Float64(105),
+				}}
+			},
+			wantFields: []string{
+				"toleranceOverrides[0].labelSelector.matchLabels",
+				"toleranceOverrides[0].qosClasses[0]",
+				"toleranceOverrides[0].toleranceCPURate",
+				"toleranceOverrides[0].toleranceMemoryRate",
+			},
+		},
+		{
+			name: "valid node profile",
+			update: func(args *config.DynamicArgs) {
+				args.NodeProfiles = []config.NodeToleranceProfile{{
+					NodeSelector:        &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "batch"}},
+					ToleranceMemoryRate: pointer.Float64(90),
+				}}
+			},
+		},
+		{
+			name: "invalid node profile",
+			update: func(args *config.DynamicArgs) {
+				args.NodeProfiles = []config.NodeToleranceProfile{{
+					ToleranceCPURate: pointer.Float64(200),
+				}}
+			},
+			wantFields: []string{"nodeProfiles[0].nodeSelector", "nodeProfiles[0].toleranceCPURate"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := defaultArgs(t)
+			tt.update(args)
+
+			errs := ValidateDynamicArgs(nil, args)
+			if len(errs) != len(tt.wantFields) {
+				t.Fatalf("ValidateDynamicArgs() = %v, want errors on %v", errs, tt.wantFields)
+			}
+			for i, err := range errs {
+				if err.Field != tt.wantFields[i] {
+					t.Errorf("ValidateDynamicArgs() error %d on %v, want %v: %v", i, err.Field, tt.wantFields[i], err)
+				}
+			}
+		})
+	}
+}
+
+// TestValidateDynamicArgsPath checks that the errors are rooted at the
+// given path.
+func TestValidateDynamicArgsPath(t *testing.T) {
+	args := defaultArgs(t)
+	args.ToleranceCPURate = 101
+
+	errs := ValidateDynamicArgs(field.NewPath("args"), args)
+	if len(errs) != 1 || errs[0].Field != "args.toleranceCPURate" {
+		t.Errorf("ValidateDynamicArgs() = %v, want one error on args.toleranceCPURate", errs)
+	}
+}
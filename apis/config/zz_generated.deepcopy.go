@@ -22,13 +22,90 @@ limitations under the License.
 package config
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DebugArgs) DeepCopyInto(out *DebugArgs) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DebugArgs.
+func (in *DebugArgs) DeepCopy() *DebugArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(DebugArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DegradationArgs) DeepCopyInto(out *DegradationArgs) {
+	*out = *in
+	out.LastKnownGoodTTL = in.LastKnownGoodTTL
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DegradationArgs.
+func (in *DegradationArgs) DeepCopy() *DegradationArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(DegradationArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DynamicArgs) DeepCopyInto(out *DynamicArgs) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
+	in.Ranking.DeepCopyInto(&out.Ranking)
+	out.Aggregation = in.Aggregation
+	in.MetricsProvider.DeepCopyInto(&out.MetricsProvider)
+	out.Scrape = in.Scrape
+	out.Staleness = in.Staleness
+	if in.ToleranceOverrides != nil {
+		in, out := &in.ToleranceOverrides, &out.ToleranceOverrides
+		*out = make([]ToleranceOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeProfiles != nil {
+		in, out := &in.NodeProfiles, &out.NodeProfiles
+		*out = make([]NodeToleranceProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RequestToleranceCPURate != nil {
+		in, out := &in.RequestToleranceCPURate, &out.RequestToleranceCPURate
+		*out = new(float64)
+		**out = **in
+	}
+	if in.RequestToleranceMemoryRate != nil {
+		in, out := &in.RequestToleranceMemoryRate, &out.RequestToleranceMemoryRate
+		*out = new(float64)
+		**out = **in
+	}
+	out.PodUsageEstimation = in.PodUsageEstimation
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourceTolerance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.NodePressure = in.NodePressure
+	out.Events = in.Events
+	out.Debug = in.Debug
+	out.Degradation = in.Degradation
+	out.CacheSyncTimeout = in.CacheSyncTimeout
 	return
 }
 
@@ -49,3 +126,299 @@ func (in *DynamicArgs) DeepCopyObject() runtime.Object {
 	}
 	return nil
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventsArgs) DeepCopyInto(out *EventsArgs) {
+	*out = *in
+	out.Interval = in.Interval
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventsArgs.
+func (in *EventsArgs) DeepCopy() *EventsArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(EventsArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsAggregationArgs) DeepCopyInto(out *MetricsAggregationArgs) {
+	*out = *in
+	out.HalfLife = in.HalfLife
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsAggregationArgs.
+func (in *MetricsAggregationArgs) DeepCopy() *MetricsAggregationArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsAggregationArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsProviderArgs) DeepCopyInto(out *MetricsProviderArgs) {
+	*out = *in
+	in.Prometheus.DeepCopyInto(&out.Prometheus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsProviderArgs.
+func (in *MetricsProviderArgs) DeepCopy() *MetricsProviderArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsProviderArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePressureArgs) DeepCopyInto(out *NodePressureArgs) {
+	*out = *in
+	out.Cooldown = in.Cooldown
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePressureArgs.
+func (in *NodePressureArgs) DeepCopy() *NodePressureArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePressureArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeRankingArgs) DeepCopyInto(out *NodeRankingArgs) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]RankingRule, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeRankingArgs.
+func (in *NodeRankingArgs) DeepCopy() *NodeRankingArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeRankingArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeToleranceProfile) DeepCopyInto(out *NodeToleranceProfile) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ToleranceCPURate != nil {
+		in, out := &in.ToleranceCPURate, &out.ToleranceCPURate
+		*out = new(float64)
+		**out = **in
+	}
+	if in.ToleranceMemoryRate != nil {
+		in, out := &in.ToleranceMemoryRate, &out.ToleranceMemoryRate
+		*out = new(float64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeToleranceProfile.
+func (in *NodeToleranceProfile) DeepCopy() *NodeToleranceProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeToleranceProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodUsageEstimationArgs) DeepCopyInto(out *PodUsageEstimationArgs) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodUsageEstimationArgs.
+func (in *PodUsageEstimationArgs) DeepCopy() *PodUsageEstimationArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(PodUsageEstimationArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusArgs) DeepCopyInto(out *PrometheusArgs) {
+	*out = *in
+	out.Timeout = in.Timeout
+	if in.ResourceQueries != nil {
+		in, out := &in.ResourceQueries, &out.ResourceQueries
+		*out = make([]PrometheusResourceQuery, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusArgs.
+func (in *PrometheusArgs) DeepCopy() *PrometheusArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusResourceQuery) DeepCopyInto(out *PrometheusResourceQuery) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusResourceQuery.
+func (in *PrometheusResourceQuery) DeepCopy() *PrometheusResourceQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusResourceQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RankingRule) DeepCopyInto(out *RankingRule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RankingRule.
+func (in *RankingRule) DeepCopy() *RankingRule {
+	if in == nil {
+		return nil
+	}
+	out := new(RankingRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceTolerance) DeepCopyInto(out *ResourceTolerance) {
+	*out = *in
+	if in.ToleranceRate != nil {
+		in, out := &in.ToleranceRate, &out.ToleranceRate
+		*out = new(float64)
+		**out = **in
+	}
+	if in.RequestToleranceRate != nil {
+		in, out := &in.RequestToleranceRate, &out.RequestToleranceRate
+		*out = new(float64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceTolerance.
+func (in *ResourceTolerance) DeepCopy() *ResourceTolerance {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceTolerance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScrapeArgs) DeepCopyInto(out *ScrapeArgs) {
+	*out = *in
+	out.Interval = in.Interval
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScrapeArgs.
+func (in *ScrapeArgs) DeepCopy() *ScrapeArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(ScrapeArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StalenessArgs) DeepCopyInto(out *StalenessArgs) {
+	*out = *in
+	out.MaxAge = in.MaxAge
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StalenessArgs.
+func (in *StalenessArgs) DeepCopy() *StalenessArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(StalenessArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToleranceOverride) DeepCopyInto(out *ToleranceOverride) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PriorityClassNames != nil {
+		in, out := &in.PriorityClassNames, &out.PriorityClassNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.QOSClasses != nil {
+		in, out := &in.QOSClasses, &out.QOSClasses
+		*out = make([]corev1.PodQOSClass, len(*in))
+		copy(*out, *in)
+	}
+	if in.ToleranceCPURate != nil {
+		in, out := &in.ToleranceCPURate, &out.ToleranceCPURate
+		*out = new(float64)
+		**out = **in
+	}
+	if in.ToleranceMemoryRate != nil {
+		in, out := &in.ToleranceMemoryRate, &out.ToleranceMemoryRate
+		*out = new(float64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToleranceOverride.
+func (in *ToleranceOverride) DeepCopy() *ToleranceOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ToleranceOverride)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,142 @@
+package dynamic
+
+import (
+	"math"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+
+	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
+)
+
+// Aggregator reduces the metrics window of a node, oldest sample first,
+// to a single sample.
+type Aggregator interface {
+	Aggregate(window []*metricsv1beta1.NodeMetrics) *metricsv1beta1.NodeMetrics
+}
+
+// NewAggregator new aggregator of the given type
+func NewAggregator(args config.MetricsAggregationArgs) Aggregator {
+	switch args.Type {
+	case config.MeanAggregation:
+		return usageAggregator(mean)
+	case config.MaxAggregation:
+		return usageAggregator(maximum)
+	case config.P90Aggregation:
+		return usageAggregator(percentile(90))
+	case config.P95Aggregation:
+		return usageAggregator(percentile(95))
+	case config.EWMAAggregation:
+		return &ewmaAggregator{halfLife: args.HalfLife.Seconds()}
+	}
+	return latestAggregator{}
+}
+
+type latestAggregator struct{}
+
+func (latestAggregator) Aggregate(window []*metricsv1beta1.NodeMetrics) *metricsv1beta1.NodeMetrics {
+	if len(window) == 0 {
+		return nil
+	}
+	return window[len(window)-1]
+}
+
+// usageAggregator reduces the milli values of every resource with fn.
+type usageAggregator func(values []float64) float64
+
+func (fn usageAggregator) Aggregate(window []*metricsv1beta1.NodeMetrics) *metricsv1beta1.NodeMetrics {
+	return aggregate(window, func(values, _ []float64) float64 {
+		return fn(values)
+	})
+}
+
+type ewmaAggregator struct {
+	halfLife float64
+}
+
+func (a *ewmaAggregator) Aggregate(window []*metricsv1beta1.NodeMetrics) *metricsv1beta1.NodeMetrics {
+	return aggregate(window, func(values, ages []float64) float64 {
+		var sum, weights float64
+		for i, v := range values {
+			w := 1.0
+			if a.halfLife > 0 {
+				w = math.Pow(0.5, ages[i]/a.halfLife)
+			}
+			sum += w * v
+			weights += w
+		}
+		if weights == 0 {
+			return 0
+		}
+		return sum / weights
+	})
+}
+
+// aggregate builds a sample that carries the timestamp and window of the
+// latest sample and the usage reduced by fn. ages are the seconds between
+// each sample and the latest one.
+func aggregate(window []*metricsv1beta1.NodeMetrics,
+	fn func(values, ages []float64) float64) *metricsv1beta1.NodeMetrics {
+	if len(window) == 0 {
+		return nil
+	}
+
+	latest := window[len(window)-1]
+	res := &metricsv1beta1.NodeMetrics{
+		ObjectMeta: latest.ObjectMeta,
+		Timestamp:  latest.Timestamp,
+		Window:     latest.Window,
+		Usage:      corev1.ResourceList{},
+	}
+
+	for name, q := range latest.Usage {
+		var values, ages []float64
+		for _, m := range window {
+			use, ok := m.Usage[name]
+			if !ok {
+				continue
+			}
+			values = append(values, float64(use.MilliValue()))
+			ages = append(ages, latest.Timestamp.Sub(m.Timestamp.Time).Seconds())
+		}
+		res.Usage[name] = *resource.NewMilliQuantity(int64(math.Round(fn(values, ages))), q.Format)
+	}
+	return res
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func maximum(values []float64) float64 {
+	var res float64
+	for _, v := range values {
+		res = math.Max(res, v)
+	}
+	return res
+}
+
+// percentile returns the nearest-rank p-th percentile.
+func percentile(p float64) func(values []float64) float64 {
+	return func(values []float64) float64 {
+		if len(values) == 0 {
+			return 0
+		}
+		sorted := append([]float64(nil), values...)
+		sort.Float64s(sorted)
+		idx := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		return sorted[idx]
+	}
+}
@@ -0,0 +1,178 @@
+package dynamic
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	"k8s.io/utils/pointer"
+
+	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
+)
+
+var aggregateNow = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// nodeSample is a usage sample taken age before aggregateNow, with the cpu
+// usage in milli cores and, if memory is not negative, the memory usage.
+func nodeSample(age time.Duration, cpu, memory int64) *metricsv1beta1.NodeMetrics {
+	m := &metricsv1beta1.NodeMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Timestamp:  metav1.NewTime(aggregateNow.Add(-age)),
+		Usage: corev1.ResourceList{
+			corev1.ResourceCPU: *resource.NewMilliQuantity(cpu, resource.DecimalSI),
+		},
+	}
+	if memory >= 0 {
+		m.Usage[corev1.ResourceMemory] = *resource.NewQuantity(memory, resource.BinarySI)
+	}
+	return m
+}
+
+// cpuWindow is a window of samples one minute apart, the last one taken at
+// aggregateNow.
+func cpuWindow(cpus ...int64) []*metricsv1beta1.NodeMetrics {
+	window := make([]*metricsv1beta1.NodeMetrics, len(cpus))
+	for i, cpu := range cpus {
+		window[i] = nodeSample(time.Duration(len(cpus)-1-i)*time.Minute, cpu, -1)
+	}
+	return window
+}
+
+func TestAggregate(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       config.MetricsAggregationArgs
+		window     []*metricsv1beta1.NodeMetrics
+		wantCPU    int64
+		wantMemory *int64
+	}{
+		{
+			name:    "latest",
+			args:    config.MetricsAggregationArgs{Type: config.LatestAggregation},
+			window:  cpuWindow(100, 300, 200),
+			wantCPU: 200,
+		},
+		{
+			name:    "mean",
+			args:    config.MetricsAggregationArgs{Type: config.MeanAggregation},
+			window:  cpuWindow(100, 300, 200),
+			wantCPU: 200,
+		},
+		{
+			name:    "mean rounds to the nearest milli",
+			args:    config.MetricsAggregationArgs{Type: config.MeanAggregation},
+			window:  cpuWindow(100, 101, 101),
+			wantCPU: 101,
+		},
+		{
+			name:    "max",
+			args:    config.MetricsAggregationArgs{Type: config.MaxAggregation},
+			window:  cpuWindow(100, 300, 200),
+			wantCPU: 300,
+		},
+		{
+			name:    "P90 of a single sample",
+			args:    config.MetricsAggregationArgs{Type: config.P90Aggregation},
+			window:  cpuWindow(100),
+			wantCPU: 100,
+		},
+		{
+			name:    "P90 of three samples is the max",
+			args:    config.MetricsAggregationArgs{Type: config.P90Aggregation},
+			window:  cpuWindow(100, 300, 200),
+			wantCPU: 300,
+		},
+		{
+			name:    "P90 of ten samples is the ninth",
+			args:    config.MetricsAggregationArgs{Type: config.P90Aggregation},
+			window:  cpuWindow(1000, 900, 800, 700, 600, 500, 400, 300, 200, 100),
+			wantCPU: 900,
+		},
+		{
+			name:    "P95 of ten samples is the max",
+			args:    config.MetricsAggregationArgs{Type: config.P95Aggregation},
+			window:  cpuWindow(1000, 900, 800, 700, 600, 500, 400, 300, 200, 100),
+			wantCPU: 1000,
+		},
+		{
+			name:    "P95 of twenty samples is the nineteenth",
+			args:    config.MetricsAggregationArgs{Type: config.P95Aggregation},
+			window:  cpuWindow(2000, 1900, 1800, 1700, 1600, 1500, 1400, 1300, 1200, 1100, 1000, 900, 800, 700, 600, 500, 400, 300, 200, 100),
+			wantCPU: 1900,
+		},
+		{
+			name: "EWMA halves the weight every half life",
+			args: config.MetricsAggregationArgs{Type: config.EWMAAggregation, HalfLife: metav1.Duration{Duration: time.Minute}},
+			// (0.25*100 + 0.5*100 + 1*450) / 1.75
+			window:  cpuWindow(100, 100, 450),
+			wantCPU: 300,
+		},
+		{
+			name:    "EWMA weighs by sample age, not position",
+			args:    config.MetricsAggregationArgs{Type: config.EWMAAggregation, HalfLife: metav1.Duration{Duration: time.Minute}},
+			window:  []*metricsv1beta1.NodeMetrics{nodeSample(2*time.Minute, 100, -1), nodeSample(0, 400, -1)},
+			wantCPU: 340,
+		},
+		{
+			name: "resource missing from older samples",
+			args: config.MetricsAggregationArgs{Type: config.MeanAggregation},
+			window: []*metricsv1beta1.NodeMetrics{
+				nodeSample(2*time.Minute, 100, -1),
+				nodeSample(time.Minute, 200, 1024),
+				nodeSample(0, 300, 3072),
+			},
+			wantCPU:    200,
+			wantMemory: pointer.Int64(2048),
+		},
+		{
+			name: "resource missing from the latest sample is dropped",
+			args: config.MetricsAggregationArgs{Type: config.MaxAggregation},
+			window: []*metricsv1beta1.NodeMetrics{
+				nodeSample(time.Minute, 100, 4096),
+				nodeSample(0, 200, -1),
+			},
+			wantCPU: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewAggregator(tt.args).Aggregate(tt.window)
+			if got == nil {
+				t.Fatalf("Aggregate() = nil")
+			}
+			latest := tt.window[len(tt.window)-1]
+			if !got.Timestamp.Equal(&latest.Timestamp) {
+				t.Errorf("timestamp = %v, want the latest %v", got.Timestamp, latest.Timestamp)
+			}
+			if cpu := got.Usage[corev1.ResourceCPU]; cpu.MilliValue() != tt.wantCPU {
+				t.Errorf("cpu = %vm, want %vm", cpu.MilliValue(), tt.wantCPU)
+			}
+
+			memory, ok := got.Usage[corev1.ResourceMemory]
+			switch {
+			case tt.wantMemory == nil && ok:
+				t.Errorf("memory = %v, want none", memory.String())
+			case tt.wantMemory != nil && !ok:
+				t.Errorf("memory missing, want %v", *tt.wantMemory)
+			case tt.wantMemory != nil && memory.Value() != *tt.wantMemory:
+				t.Errorf("memory = %v, want %v", memory.Value(), *tt.wantMemory)
+			}
+		})
+	}
+}
+
+func TestAggregateEmptyWindow(t *testing.T) {
+	for _, aggregation := range []config.AggregationType{
+		config.LatestAggregation, config.MeanAggregation, config.MaxAggregation,
+		config.P90Aggregation, config.P95Aggregation, config.EWMAAggregation,
+	} {
+		args := config.MetricsAggregationArgs{Type: aggregation, HalfLife: metav1.Duration{Duration: time.Minute}}
+		if got := NewAggregator(args).Aggregate(nil); got != nil {
+			t.Errorf("%v: Aggregate(nil) = %v, want nil", aggregation, got)
+		}
+	}
+}
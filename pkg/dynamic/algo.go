@@ -9,178 +9,350 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	coreinformerv1 "k8s.io/client-go/informers/core/v1"
-	"k8s.io/client-go/kubernetes"
 	rest "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
-	"k8s.io/klog"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	resourcehelper "k8s.io/kubernetes/pkg/api/v1/resource"
+	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
 	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
-	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
+
+	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
 )
 
+// metricsWindowSize is the number of samples kept per node and per workload.
+const metricsWindowSize = 15
+
 var (
-	reSyncPeriod     = time.Minute
 	nodePodIndexName = "node_pod"
+	// unassignedPodKey groups the pods that are not bound to a node yet in
+	// the node_pod index.
+	unassignedPodKey = ""
+	// nodePodIndexerMu serializes the profiles adding the node_pod index
+	// to the shared pod informer.
+	nodePodIndexerMu sync.Mutex
 )
 
 // Cache cache
 type Cache interface {
-	GetNodeInfos(nodeNames []string, pod *corev1.Pod) NodeInfos
-	GetNodeInfo(nodeName string, pod *corev1.Pod) NodeInfo
+	// GetNodeInfos and GetNodeInfo also count the pods the scheduler has
+	// assumed on the nodes, from the NodeInfos of the scheduling cycle. The
+	// scheduler rewrites them between cycles, so callers outside of a cycle
+	// pass nil and only count the bound pods.
+	GetNodeInfos(nodeInfos []*framework.NodeInfo, pod *corev1.Pod) NodeInfos
+	GetNodeInfo(nodeName string, pod *corev1.Pod, nodeInfo *framework.NodeInfo) NodeInfo
+	GetWorkloadProfile(pod *corev1.Pod) (WorkloadProfile, bool)
+	// AddScrapeHandler registers a handler called with the infos of all
+	// nodes, excluding any incoming pod, after every node metrics scrape.
+	AddScrapeHandler(handler func(NodeInfos))
+	// GetNodeMetricsWindow returns the raw metrics window of the node, the
+	// oldest sample first.
+	GetNodeMetricsWindow(nodeName string) []*metricsv1beta1.NodeMetrics
+	// Degraded reports whether the metrics provider is down.
+	Degraded() bool
+	// Ready returns an error until the informers are synced and a node
+	// metrics scrape succeeded, or the cache turned degraded.
+	Ready() error
 	Init() error
 	Close()
 }
 
 // NodeCache
 type NodeCache struct {
-	metricsClient metricsclientset.Interface
-	clientSet     kubernetes.Interface
-	nodeInformer  coreinformerv1.NodeInformer
-	podInformer   cache.SharedIndexInformer
-	stopCh        chan struct{}
-	nodeMetrics   map[string]*list.List
+	provider     MetricsProvider
+	nodeInformer coreinformerv1.NodeInformer
+	podInformer  coreinformerv1.PodInformer
+	stopCh       chan struct{}
+	nodeMetrics  map[string]*list.List
+	aggregator   Aggregator
+	scrapeArgs   config.ScrapeArgs
+	limiter      flowcontrol.RateLimiter
+	staleness    config.StalenessArgs
+	degradation  config.DegradationArgs
+	health       metricsHealth
+	syncTimeout  time.Duration
+	readiness    cacheReadiness
+	// resources are the extra resources of DynamicArgs.Resources.
+	resources []corev1.ResourceName
+	// profiles are only scraped for the History pod usage estimator.
+	scrapePods bool
+	profiles   workloadProfiles
+	// lastPressure is the last time each node was seen under pressure.
+	lastPressure map[string]time.Time
+	// scrapeHandlers are called after every node metrics scrape.
+	scrapeHandlers []func(NodeInfos)
 	sync.RWMutex
 }
 
-// NewNodeCache new node cache
-func NewNodeCache(kc *rest.Config) (*NodeCache, error) {
-	metricsClient, err := metricsclientset.NewForConfig(kc)
+// NewNodeCache new node cache on top of the scheduler's shared informers
+func NewNodeCache(kc *rest.Config, args *config.DynamicArgs,
+	informerFactory informers.SharedInformerFactory) (*NodeCache, error) {
+	provider, err := NewMetricsProvider(kc, args.MetricsProvider)
 	if err != nil {
 		return nil, err
 	}
 
-	client, err := kubernetes.NewForConfig(kc)
-	if err != nil {
-		return nil, err
+	resources := make([]corev1.ResourceName, 0, len(args.Resources))
+	for _, r := range args.Resources {
+		resources = append(resources, r.Name)
 	}
 
 	nc := NodeCache{
-		clientSet:     client,
-		stopCh:        make(chan struct{}),
-		metricsClient: metricsClient,
-		nodeMetrics:   make(map[string]*list.List),
+		nodeInformer: informerFactory.Core().V1().Nodes(),
+		podInformer:  informerFactory.Core().V1().Pods(),
+		stopCh:       make(chan struct{}),
+		provider:     provider,
+		nodeMetrics:  make(map[string]*list.List),
+		aggregator:   NewAggregator(args.Aggregation),
+		scrapeArgs:   args.Scrape,
+		limiter:      flowcontrol.NewTokenBucketRateLimiter(float32(args.Scrape.QPS), int(args.Scrape.Concurrency)),
+		staleness:    args.Staleness,
+		degradation:  args.Degradation,
+		syncTimeout:  args.CacheSyncTimeout.Duration,
+		readiness:    cacheReadiness{err: errCacheNotSynced},
+		resources:    resources,
+		scrapePods:   args.PodUsageEstimation.Estimator == config.HistoryEstimator,
+		profiles:     workloadProfiles{samples: make(map[string][]workloadSample)},
+		lastPressure: make(map[string]time.Time),
+	}
+	if err := nc.Init(); err != nil {
+		return nil, err
 	}
-	nc.Init()
 	return &nc, nil
 }
 
-// Init init node cache
+// Init init node cache. The shared informers are started by the scheduler
+// after all plugins are built, so syncing and scraping run in background.
 func (nc *NodeCache) Init() error {
-	informerFactory := informers.NewSharedInformerFactory(nc.clientSet, reSyncPeriod)
-
-	nodeInformer := informerFactory.Core().V1().Nodes()
-	go nodeInformer.Informer().Run(nc.stopCh)
-
-	if !cache.WaitForCacheSync(nc.stopCh, nodeInformer.Informer().HasSynced) {
-		return fmt.Errorf("wait for node cache sync error")
-	}
-	klog.Info("sync node cache successful.")
-
-	podInformer := coreinformerv1.NewPodInformer(
-		nc.clientSet, metav1.NamespaceAll, 0,
-		cache.Indexers{
-			nodePodIndexName: func(obj interface{}) ([]string, error) {
-				pod, ok := obj.(*corev1.Pod)
-				if !ok {
-					return nil, fmt.Errorf("type error")
-				}
-				return []string{pod.Spec.NodeName}, nil
-			},
+	// Informer() registers the informers with the factory before it starts.
+	nodeSynced := nc.nodeInformer.Informer().HasSynced
+	podInformer := nc.podInformer.Informer()
+	if err := addNodePodIndexer(podInformer); err != nil {
+		return err
+	}
+	_, err := nc.nodeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			nc.updateNodePressure(nil, obj)
 		},
-	)
-
-	go podInformer.Run(nc.stopCh)
-
-	if !cache.WaitForCacheSync(nc.stopCh, podInformer.HasSynced) {
-		return fmt.Errorf("wait for all pod cache sync error")
+		UpdateFunc: nc.updateNodePressure,
+		DeleteFunc: nc.deleteNode,
+	})
+	if err != nil {
+		return fmt.Errorf("add node event handler err: %w", err)
 	}
-	klog.Info("sync all pod cache successful.")
-
-	nc.nodeInformer = nodeInformer
-	nc.podInformer = podInformer
 
-	for i := 0; i < 5; i++ {
-		if nc.scrapeNodeMetrics() {
-			klog.Info("init all node metrics successful.")
-			break
+	go func() {
+		if !nc.waitForCacheSync(nodeSynced, podInformer.HasSynced) {
+			klog.InfoS("Node cache closed before node and pod cache sync")
+			return
 		}
-		klog.Warningf("try init all node metrics failed, on %d times", i)
-	}
+		klog.InfoS("Synced node and pod cache")
 
-	go func() {
-		wait.Until(func() { nc.scrapeNodeMetrics() }, time.Minute, nc.stopCh)
+		for i := 0; i < 5; i++ {
+			if nc.scrapeNodeMetrics() {
+				klog.InfoS("Scraped the metrics of all nodes")
+				break
+			}
+			klog.InfoS("Failed to scrape the metrics of all nodes", "attempt", i+1)
+		}
+		// The informers are synced, Ready still waits for a successful
+		// scrape or the degraded mode.
+		nc.readiness.set(nil)
+
+		wait.Until(func() {
+			nc.scrapeNodeMetrics()
+			if nc.scrapePods {
+				nc.scrapePodMetrics()
+			}
+		}, nc.scrapeArgs.Interval.Duration, nc.stopCh)
 	}()
 
 	return nil
 }
 
-func (nc *NodeCache) calcNodeRequestResourceTotal(nodeName string,
-	pod *corev1.Pod) (totalCPU resource.Quantity, totalMemory resource.Quantity) {
-	objs, err := nc.podInformer.GetIndexer().ByIndex(nodePodIndexName, nodeName)
-	if err != nil {
-		return
+// addNodePodIndexer adds the node_pod index to the shared pod informer,
+// unless the Dynamic plugin of another profile already did.
+func addNodePodIndexer(informer cache.SharedIndexInformer) error {
+	nodePodIndexerMu.Lock()
+	defer nodePodIndexerMu.Unlock()
+
+	if _, ok := informer.GetIndexer().GetIndexers()[nodePodIndexName]; ok {
+		return nil
+	}
+	if err := informer.AddIndexers(cache.Indexers{nodePodIndexName: nodePodIndexFunc}); err != nil {
+		return fmt.Errorf("add node pod indexer err: %w", err)
+	}
+	return nil
+}
+
+// nodePodIndexFunc indexes pods by node name. Terminated pods no longer
+// hold their requests and are left out, unassigned pods are grouped under
+// unassignedPodKey.
+func nodePodIndexFunc(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil, fmt.Errorf("type error")
+	}
+	if isTerminated(pod) {
+		return nil, nil
+	}
+	if pod.Spec.NodeName == "" {
+		return []string{unassignedPodKey}, nil
 	}
+	return []string{pod.Spec.NodeName}, nil
+}
 
-	klog.V(3).Infof("get %d pod for node %v", len(objs), nodeName)
+func isTerminated(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
 
-	objs = append(objs, pod)
+// nodePods returns the pods bound to the node in the pod informer.
+func (nc *NodeCache) nodePods(nodeName string) []*corev1.Pod {
+	objs, err := nc.podInformer.Informer().GetIndexer().ByIndex(nodePodIndexName, nodeName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get pods of node from pod informer", "node", klog.KRef("", nodeName))
+		return nil
+	}
 
+	pods := make([]*corev1.Pod, 0, len(objs))
 	for _, item := range objs {
 		p, ok := item.(*corev1.Pod)
 		if !ok {
-			klog.Errorf("kind is not *corev1.Pod")
+			klog.ErrorS(nil, "Unexpected object in pod indexer", "type", fmt.Sprintf("%T", item))
 			continue
 		}
+		pods = append(pods, p)
+	}
+	return pods
+}
 
-		// TODO initContainer ?
-		for _, c := range p.Spec.Containers {
-			if c.Resources.Requests.Cpu() != nil {
-				totalCPU.Add(*c.Resources.Requests.Cpu())
+// calcNodeRequestResourceTotal sums the requests of all resources of the
+// pods on the node and of the incoming pod, if any. In a scheduling cycle
+// nodeInfo already holds the sum, assumed pods included, otherwise the pods
+// are summed from the pod informer.
+func (nc *NodeCache) calcNodeRequestResourceTotal(nodeName string, pod *corev1.Pod, nodeInfo *framework.NodeInfo) corev1.ResourceList {
+	var total corev1.ResourceList
+	if nodeInfo != nil {
+		total = requestedResourceList(nodeInfo.Requested)
+		// The scheduler keeps terminated pods in the NodeInfo until they
+		// are deleted, they no longer hold their requests.
+		for _, podInfo := range nodeInfo.Pods {
+			if isTerminated(podInfo.Pod) {
+				subtractPodRequests(total, podInfo.Pod)
 			}
+		}
+	} else {
+		total = corev1.ResourceList{}
+		pods := nc.nodePods(nodeName)
+		klog.V(5).InfoS("Summing pod requests", "node", klog.KRef("", nodeName), "pods", len(pods))
+		for _, p := range pods {
+			addPodRequests(total, p)
+		}
+	}
 
-			if c.Resources.Requests.Memory() != nil {
-				totalMemory.Add(*c.Resources.Requests.Memory())
-			}
+	if pod != nil {
+		addPodRequests(total, pod)
+	}
+	return total
+}
+
+// addPodRequests adds the effective requests of the pod to total, as
+// kube-scheduler computes them: the max of every init container and the sum
+// of the app containers, plus the pod overhead. Restartable sidecar init
+// containers need core/v1 from Kubernetes 1.28 and are counted as plain init
+// containers.
+func addPodRequests(total corev1.ResourceList, pod *corev1.Pod) {
+	reqs, _ := resourcehelper.PodRequestsAndLimits(pod)
+	for name, q := range reqs {
+		sum := total[name]
+		sum.Add(q)
+		total[name] = sum
+	}
+}
+
+func subtractPodRequests(total corev1.ResourceList, pod *corev1.Pod) {
+	reqs, _ := resourcehelper.PodRequestsAndLimits(pod)
+	for name, q := range reqs {
+		if sum, ok := total[name]; ok {
+			sum.Sub(q)
+			total[name] = sum
 		}
 	}
+}
 
-	return
+// requestedResourceList converts the requests summed by the scheduler back
+// to a resource list.
+func requestedResourceList(r *framework.Resource) corev1.ResourceList {
+	res := corev1.ResourceList{
+		corev1.ResourceCPU:              *resource.NewMilliQuantity(r.MilliCPU, resource.DecimalSI),
+		corev1.ResourceMemory:           *resource.NewQuantity(r.Memory, resource.BinarySI),
+		corev1.ResourceEphemeralStorage: *resource.NewQuantity(r.EphemeralStorage, resource.BinarySI),
+	}
+	for name, v := range r.ScalarResources {
+		format := resource.DecimalSI
+		if v1helper.IsHugePageResourceName(name) {
+			format = resource.BinarySI
+		}
+		res[name] = *resource.NewQuantity(v, format)
+	}
+	return res
 }
 
 func (nc *NodeCache) scrapeNodeMetrics() bool {
-	klog.V(3).Infof("start to scrape node metrics...")
+	klog.V(3).InfoS("Scraping node metrics")
+	start := time.Now()
 
 	nodes, err := nc.nodeInformer.Lister().List(labels.Everything())
 	if err != nil {
-		klog.Errorf("list node from node informer err: %v", err)
+		klog.ErrorS(err, "Failed to list nodes from node informer")
 		return false
 	}
 
-	for _, n := range nodes {
-		metrics, err := nc.metricsClient.MetricsV1beta1().NodeMetricses().Get(context.TODO(), n.Name, metav1.GetOptions{})
+	// A pass must not outlive the next one.
+	ctx, cancel := context.WithTimeout(context.Background(), nc.scrapeArgs.Interval.Duration)
+	defer cancel()
+
+	var metrics []*metricsv1beta1.NodeMetrics
+	if lister, ok := nc.provider.(NodeMetricsLister); ok {
+		metrics, err = lister.ListNodeMetrics(ctx, nc.scrapeArgs.PageSize)
 		if err != nil {
-			klog.Warningf("get node: %v metrics err: %v", n.Name, err)
-			continue
+			// Getting the nodes one by one would hit the same provider
+			// with as many requests as there are nodes.
+			scrapeErrors.WithLabelValues(nodeScrapeTarget).Inc()
+			klog.ErrorS(err, "Failed to list node metrics")
+			nc.updateMetricsHealth(false)
+			return false
 		}
+	} else {
+		metrics = nc.getAllNodeMetrics(ctx, nodes)
+	}
+	scrapeDuration.WithLabelValues(nodeScrapeTarget).Observe(time.Since(start).Seconds())
 
-		klog.V(3).Infof("get %v node metrics success", n.Name)
+	klog.V(3).InfoS("Scraped node metrics", "nodes", len(nodes), "metrics", len(metrics), "duration", time.Since(start))
 
-		nc.Lock()
-		if _, ok := nc.nodeMetrics[n.Name]; !ok {
-			nc.nodeMetrics[n.Name] = list.New()
+	nc.Lock()
+	nc.pruneNodeMetrics(nodes)
+	for _, m := range metrics {
+		if _, ok := nc.nodeMetrics[m.Name]; !ok {
+			nc.nodeMetrics[m.Name] = list.New()
 		}
-		nc.nodeMetrics[n.Name].PushBack(metrics)
-		if nc.nodeMetrics[n.Name].Len() > 15 {
-			nc.nodeMetrics[n.Name].Remove(nc.nodeMetrics[n.Name].Front())
+		nc.nodeMetrics[m.Name].PushBack(m)
+		if nc.nodeMetrics[m.Name].Len() > metricsWindowSize {
+			nc.nodeMetrics[m.Name].Remove(nc.nodeMetrics[m.Name].Front())
 		}
-		nc.Unlock()
-
-		time.Sleep(time.Millisecond * 500)
 	}
+	nc.Unlock()
+
+	// An empty pass over a non empty cluster means the provider is down.
+	nc.updateMetricsHealth(len(metrics) > 0 || len(nodes) == 0)
+	nc.recordCache(nodes)
 
 	nc.RLock()
 	res := len(nc.nodeMetrics) == len(nodes)
@@ -189,34 +361,144 @@ func (nc *NodeCache) scrapeNodeMetrics() bool {
 	return res
 }
 
-func (nc *NodeCache) getNodeMetrics(nodeName string) *metricsv1beta1.NodeMetrics {
+// getAllNodeMetrics gets the metrics of every node by a pool of Concurrency
+// workers sharing a QPS token bucket. Failures are logged once per pass, a
+// provider down would otherwise log an error per node.
+func (nc *NodeCache) getAllNodeMetrics(ctx context.Context, nodes []*corev1.Node) []*metricsv1beta1.NodeMetrics {
+	all := make([]*metricsv1beta1.NodeMetrics, len(nodes))
+	errs := make([]error, len(nodes))
+	workqueue.ParallelizeUntil(ctx, int(nc.scrapeArgs.Concurrency), len(nodes), func(i int) {
+		if err := nc.limiter.Wait(ctx); err != nil {
+			return
+		}
+
+		metrics, err := nc.provider.NodeMetrics(ctx, nodes[i].Name)
+		if err != nil {
+			scrapeErrors.WithLabelValues(nodeScrapeTarget).Inc()
+			klog.V(4).InfoS("Failed to get node metrics", "node", klog.KObj(nodes[i]), "err", err)
+			errs[i] = err
+			return
+		}
+		all[i] = metrics
+	})
+
+	res := make([]*metricsv1beta1.NodeMetrics, 0, len(all))
+	var failed int
+	var lastErr error
+	for i, m := range all {
+		if m != nil {
+			res = append(res, m)
+		}
+		if errs[i] != nil {
+			failed++
+			lastErr = errs[i]
+		}
+	}
+	if failed > 0 {
+		klog.ErrorS(lastErr, "Failed to get the metrics of some nodes", "failed", failed, "nodes", len(nodes))
+	}
+	return res
+}
+
+// deleteNode drops the metrics of a node deleted from the node informer.
+func (nc *NodeCache) deleteNode(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		klog.ErrorS(nil, "Unexpected object in node informer", "type", fmt.Sprintf("%T", obj))
+		return
+	}
+
+	nc.Lock()
+	delete(nc.nodeMetrics, node.Name)
+	delete(nc.lastPressure, node.Name)
+	nc.Unlock()
+	nc.forgetNode(node.Name)
+}
+
+// pruneNodeMetrics drops the metrics, pressure time and metric series of
+// the nodes that no longer exist, in case a delete event was missed. Must be
+// called with the lock held.
+func (nc *NodeCache) pruneNodeMetrics(nodes []*corev1.Node) {
+	exist := make(map[string]struct{}, len(nodes))
+	for _, n := range nodes {
+		exist[n.Name] = struct{}{}
+	}
+	gone := sets.NewString()
+	for name := range nc.nodeMetrics {
+		if _, ok := exist[name]; !ok {
+			gone.Insert(name)
+		}
+	}
+	for name := range nc.lastPressure {
+		if _, ok := exist[name]; !ok {
+			gone.Insert(name)
+		}
+	}
+	for name := range gone {
+		delete(nc.nodeMetrics, name)
+		delete(nc.lastPressure, name)
+		nc.forgetNode(name)
+	}
+}
+
+// GetNodeMetricsWindow returns the raw metrics window of the node,
+// including the samples older than the max age.
+func (nc *NodeCache) GetNodeMetricsWindow(nodeName string) []*metricsv1beta1.NodeMetrics {
 	nc.RLock()
 	defer nc.RUnlock()
 
-	if nc.nodeMetrics[nodeName] == nil {
+	l := nc.nodeMetrics[nodeName]
+	if l == nil {
+		return nil
+	}
+	window := make([]*metricsv1beta1.NodeMetrics, 0, l.Len())
+	for e := l.Front(); e != nil; e = e.Next() {
+		window = append(window, e.Value.(*metricsv1beta1.NodeMetrics))
+	}
+	return window
+}
+
+// getNodeMetrics aggregates the samples of the node younger than maxAge,
+// it returns nil if there is none.
+func (nc *NodeCache) getNodeMetrics(nodeName string, maxAge time.Duration) *metricsv1beta1.NodeMetrics {
+	nc.RLock()
+	l := nc.nodeMetrics[nodeName]
+	if l == nil {
+		nc.RUnlock()
 		return nil
 	}
 
-	// TODO consider better algorithm
-	e := nc.nodeMetrics[nodeName].Back()
-	if e == nil {
+	window := make([]*metricsv1beta1.NodeMetrics, 0, l.Len())
+	for e := l.Front(); e != nil; e = e.Next() {
+		m := e.Value.(*metricsv1beta1.NodeMetrics)
+		if time.Since(m.Timestamp.Time) > maxAge {
+			continue
+		}
+		window = append(window, m)
+	}
+	nc.RUnlock()
+
+	if len(window) == 0 {
 		return nil
 	}
 
-	return e.Value.(*metricsv1beta1.NodeMetrics)
+	return nc.aggregator.Aggregate(window)
 }
 
 // GetNodeInfos get nodes cpu state
-func (nc *NodeCache) GetNodeInfos(nodeNames []string, pod *corev1.Pod) NodeInfos {
-	infos := make([]NodeInfo, len(nodeNames))
+func (nc *NodeCache) GetNodeInfos(nodeInfos []*framework.NodeInfo, pod *corev1.Pod) NodeInfos {
+	infos := make([]NodeInfo, len(nodeInfos))
 
 	wg := sync.WaitGroup{}
-	for i, name := range nodeNames {
+	for i, nodeInfo := range nodeInfos {
 		wg.Add(1)
-		go func(i int, name string) {
+		go func(i int, nodeInfo *framework.NodeInfo) {
 			defer wg.Done()
-			infos[i] = nc.GetNodeInfo(name, pod)
-		}(i, name)
+			infos[i] = nc.GetNodeInfo(nodeInfo.Node().Name, pod, nodeInfo)
+		}(i, nodeInfo)
 	}
 
 	wg.Wait()
@@ -224,7 +506,7 @@ func (nc *NodeCache) GetNodeInfos(nodeNames []string, pod *corev1.Pod) NodeInfos
 }
 
 // GetNodeInfo get single node cpu state
-func (nc *NodeCache) GetNodeInfo(nodeName string, pod *corev1.Pod) NodeInfo {
+func (nc *NodeCache) GetNodeInfo(nodeName string, pod *corev1.Pod, nodeInfo *framework.NodeInfo) NodeInfo {
 	info := NodeInfo{
 		NodeName:          nodeName,
 		RealCPURate:       100,
@@ -235,39 +517,90 @@ func (nc *NodeCache) GetNodeInfo(nodeName string, pod *corev1.Pod) NodeInfo {
 
 	node, err := nc.nodeInformer.Lister().Get(nodeName)
 	if err != nil {
-		klog.Errorf("ge%v err: %v", nodeName, err)
+		klog.ErrorS(err, "Failed to get node from node informer", "node", klog.KRef("", nodeName))
 		return info
 	}
 
 	info.Labels = node.Labels
 	info.Annotations = node.Annotations
+	info.Pressure = nodePressure(node)
+	nc.RLock()
+	info.LastPressure = nc.lastPressure[nodeName]
+	nc.RUnlock()
+
+	totalRequest := nc.calcNodeRequestResourceTotal(nodeName, pod, nodeInfo)
+	totalRequestCPU, totalRequestMemory := totalRequest[corev1.ResourceCPU], totalRequest[corev1.ResourceMemory]
+
+	lastTotalCPU := *node.Status.Allocatable.Cpu()
+	lastTotalCPU.Sub(totalRequestCPU)
+	info.RemainAllocatableCPU = lastTotalCPU
+	info.RequestCPURate = 100 * (float64(totalRequestCPU.MilliValue()) / float64(node.Status.Allocatable.Cpu().MilliValue()))
+
+	lastTotalMemory := *node.Status.Allocatable.Memory()
+	lastTotalMemory.Sub(totalRequestMemory)
+	info.RemainAllocatableMemory = lastTotalMemory
+	info.RequestMemoryRate = 100 * (float64(totalRequestMemory.MilliValue()) / float64(node.Status.Allocatable.Memory().MilliValue()))
 
-	metrics := nc.getNodeMetrics(nodeName)
+	info.Resources = make(map[corev1.ResourceName]ResourceRate, len(nc.resources))
+	for _, name := range nc.resources {
+		allocatable, ok := node.Status.Allocatable[name]
+		if !ok || allocatable.IsZero() {
+			continue
+		}
+		request := totalRequest[name]
+		info.Resources[name] = ResourceRate{
+			Request: 100 * (float64(request.MilliValue()) / float64(allocatable.MilliValue())),
+		}
+	}
+
+	metrics := nc.getNodeMetrics(nodeName, nc.staleness.MaxAge.Duration)
+	// Until the cache is ready, missing metrics are not the node's fault.
+	if metrics == nil && (nc.Degraded() || nc.Ready() != nil) {
+		info.Degraded = true
+		if nc.degradation.Policy == config.LastKnownGoodDegradation {
+			metrics = nc.getNodeMetrics(nodeName, nc.degradation.LastKnownGoodTTL.Duration)
+		}
+	}
 	if metrics == nil {
+		info.Stale = true
+		if info.Degraded || nc.staleness.Policy != config.RejectStaleMetrics {
+			// Requests are the best guess of the usage of the node.
+			info.RealCPURate = info.RequestCPURate
+			info.RealMemoryRate = info.RequestMemoryRate
+		}
 		return info
 	}
 
-	totalRequestCPU, totalRequestMemory := nc.calcNodeRequestResourceTotal(nodeName, pod)
+	info.MetricsTimestamp = metrics.Timestamp.Time
 	if use, ok := metrics.Usage[corev1.ResourceCPU]; ok {
-		lastTotalCPU := *node.Status.Allocatable.Cpu()
-		lastTotalCPU.Sub(totalRequestCPU)
-		info.RemainAllocatableCPU = lastTotalCPU
-		info.RequestCPURate = 100 * (float64(totalRequestCPU.MilliValue()) / float64(node.Status.Allocatable.Cpu().MilliValue()))
 		info.RealCPURate = 100 * (float64(use.MilliValue()) / float64(node.Status.Capacity.Cpu().MilliValue()))
 	}
-
 	if use, ok := metrics.Usage[corev1.ResourceMemory]; ok {
-		lastTotalMemory := *node.Status.Allocatable.Memory()
-		lastTotalMemory.Sub(totalRequestMemory)
-		info.RemainAllocatableMemory = lastTotalMemory
-		info.RequestMemoryRate = 100 * (float64(totalRequestMemory.MilliValue()) / float64(node.Status.Allocatable.Memory().MilliValue()))
 		info.RealMemoryRate = 100 * (float64(use.MilliValue()) / float64(node.Status.Capacity.Memory().MilliValue()))
 	}
+	for name, rate := range info.Resources {
+		use, ok := metrics.Usage[name]
+		capacity := node.Status.Capacity[name]
+		if !ok || capacity.IsZero() {
+			continue
+		}
+		rate.Real = 100 * (float64(use.MilliValue()) / float64(capacity.MilliValue()))
+		rate.HasReal = true
+		info.Resources[name] = rate
+	}
 	return info
 }
 
+// AddScrapeHandler registers a handler called after every node metrics
+// scrape.
+func (nc *NodeCache) AddScrapeHandler(handler func(NodeInfos)) {
+	nc.Lock()
+	defer nc.Unlock()
+	nc.scrapeHandlers = append(nc.scrapeHandlers, handler)
+}
+
 // Close close every thing
 func (nc *NodeCache) Close() {
-	klog.Infof("close node cache")
+	klog.InfoS("Closing node cache")
 	close(nc.stopCh)
 }
@@ -0,0 +1,230 @@
+package dynamic
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+
+	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
+)
+
+// TestAddNodePodIndexerTwice checks that a second profile enabling Dynamic
+// reuses the node_pod index of the shared pod informer.
+func TestAddNodePodIndexerTwice(t *testing.T) {
+	factory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	informer := factory.Core().V1().Pods().Informer()
+
+	for i := 0; i < 2; i++ {
+		if err := addNodePodIndexer(informer); err != nil {
+			t.Fatalf("addNodePodIndexer() call %d err: %v", i+1, err)
+		}
+	}
+	if _, ok := informer.GetIndexer().GetIndexers()[nodePodIndexName]; !ok {
+		t.Errorf("pod informer has no %v index", nodePodIndexName)
+	}
+}
+
+func requestPod(name, nodeName string, phase corev1.PodPhase, cpu, memory string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name)},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{{
+				Name: "app",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(cpu),
+						corev1.ResourceMemory: resource.MustParse(memory),
+					},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+// TestCalcNodeRequestResourceTotal checks that the pods assumed in a
+// scheduling cycle are counted and that terminated pods are not, with and
+// without the NodeInfo of the cycle.
+func TestCalcNodeRequestResourceTotal(t *testing.T) {
+	factory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	informer := factory.Core().V1().Pods().Informer()
+	if err := addNodePodIndexer(informer); err != nil {
+		t.Fatalf("addNodePodIndexer() err: %v", err)
+	}
+
+	bound := requestPod("bound", "node-1", corev1.PodRunning, "1", "1Gi")
+	terminated := requestPod("terminated", "node-1", corev1.PodSucceeded, "2", "2Gi")
+	other := requestPod("other", "node-2", corev1.PodRunning, "4", "4Gi")
+	assumed := requestPod("assumed", "node-1", corev1.PodPending, "500m", "512Mi")
+	incoming := requestPod("incoming", "", corev1.PodPending, "250m", "256Mi")
+	for _, p := range []*corev1.Pod{bound, terminated, other} {
+		if err := informer.GetIndexer().Add(p); err != nil {
+			t.Fatalf("adding pod %v err: %v", p.Name, err)
+		}
+	}
+
+	nodeInfo := framework.NewNodeInfo(bound, terminated, assumed)
+	nodeInfo.SetNode(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}})
+
+	nc := &NodeCache{podInformer: factory.Core().V1().Pods()}
+
+	tests := []struct {
+		name       string
+		pod        *corev1.Pod
+		nodeInfo   *framework.NodeInfo
+		wantCPU    string
+		wantMemory string
+	}{
+		{
+			name:       "bound pods out of a cycle",
+			wantCPU:    "1",
+			wantMemory: "1Gi",
+		},
+		{
+			name:       "bound pods and the incoming pod out of a cycle",
+			pod:        incoming,
+			wantCPU:    "1250m",
+			wantMemory: "1280Mi",
+		},
+		{
+			name:       "assumed pods in a cycle",
+			nodeInfo:   nodeInfo,
+			wantCPU:    "1500m",
+			wantMemory: "1536Mi",
+		},
+		{
+			name:       "assumed pods and the incoming pod in a cycle",
+			pod:        incoming,
+			nodeInfo:   nodeInfo,
+			wantCPU:    "1750m",
+			wantMemory: "1792Mi",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total := nc.calcNodeRequestResourceTotal("node-1", tt.pod, tt.nodeInfo)
+			if got, want := total[corev1.ResourceCPU], resource.MustParse(tt.wantCPU); got.Cmp(want) != 0 {
+				t.Errorf("cpu requests = %v, want %v", got.String(), want.String())
+			}
+			if got, want := total[corev1.ResourceMemory], resource.MustParse(tt.wantMemory); got.Cmp(want) != 0 {
+				t.Errorf("memory requests = %v, want %v", got.String(), want.String())
+			}
+		})
+	}
+}
+
+// fakeListerProvider lists the node metrics in one call and counts the
+// calls for a single node.
+type fakeListerProvider struct {
+	metrics []*metricsv1beta1.NodeMetrics
+	err     error
+	gets    int32
+}
+
+func (p *fakeListerProvider) NodeMetrics(_ context.Context, nodeName string) (*metricsv1beta1.NodeMetrics, error) {
+	atomic.AddInt32(&p.gets, 1)
+	return nil, fmt.Errorf("no metrics for node %v", nodeName)
+}
+
+func (p *fakeListerProvider) ListNodeMetrics(_ context.Context, _ int64) ([]*metricsv1beta1.NodeMetrics, error) {
+	return p.metrics, p.err
+}
+
+// TestScrapeNodeMetricsLister checks that a provider able to list the node
+// metrics is never asked for the nodes one by one, and that a failed list
+// counts as a failed scrape while an empty one does not fall back.
+func TestScrapeNodeMetricsLister(t *testing.T) {
+	tests := []struct {
+		name         string
+		provider     *fakeListerProvider
+		want         bool
+		wantFailures int32
+	}{
+		{
+			name: "list",
+			provider: &fakeListerProvider{metrics: []*metricsv1beta1.NodeMetrics{{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+				Timestamp:  metav1.Now(),
+			}}},
+			want: true,
+		},
+		{
+			name:         "list error",
+			provider:     &fakeListerProvider{err: fmt.Errorf("unavailable")},
+			wantFailures: 1,
+		},
+		{
+			name:         "empty list",
+			provider:     &fakeListerProvider{metrics: []*metricsv1beta1.NodeMetrics{}},
+			wantFailures: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+			nodeInformer := factory.Core().V1().Nodes()
+			podInformer := factory.Core().V1().Pods()
+			if err := addNodePodIndexer(podInformer.Informer()); err != nil {
+				t.Fatalf("addNodePodIndexer() err: %v", err)
+			}
+			if err := nodeInformer.Informer().GetIndexer().Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}); err != nil {
+				t.Fatalf("adding node err: %v", err)
+			}
+
+			nc := &NodeCache{
+				provider:     tt.provider,
+				nodeInformer: nodeInformer,
+				podInformer:  podInformer,
+				nodeMetrics:  make(map[string]*list.List),
+				scrapeArgs:   config.ScrapeArgs{Interval: metav1.Duration{Duration: time.Second}, Concurrency: 1},
+				limiter:      flowcontrol.NewFakeAlwaysRateLimiter(),
+				degradation:  config.DegradationArgs{FailureThreshold: 3},
+				lastPressure: make(map[string]time.Time),
+				profiles:     workloadProfiles{samples: make(map[string][]workloadSample)},
+			}
+
+			if got := nc.scrapeNodeMetrics(); got != tt.want {
+				t.Errorf("scrapeNodeMetrics() = %v, want %v", got, tt.want)
+			}
+			if gets := atomic.LoadInt32(&tt.provider.gets); gets != 0 {
+				t.Errorf("provider got %d nodes one by one, want none", gets)
+			}
+			if nc.health.failures != tt.wantFailures {
+				t.Errorf("failed scrapes = %d, want %d", nc.health.failures, tt.wantFailures)
+			}
+		})
+	}
+}
+
+// TestPruneNodeMetrics checks that a node deleted without a delete event
+// leaves nothing behind.
+func TestPruneNodeMetrics(t *testing.T) {
+	nc := &NodeCache{
+		nodeMetrics:  map[string]*list.List{"node-1": list.New(), "gone": list.New()},
+		lastPressure: map[string]time.Time{"node-1": time.Now(), "gone-under-pressure": time.Now()},
+	}
+	nc.pruneNodeMetrics([]*corev1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}})
+
+	if _, ok := nc.nodeMetrics["node-1"]; !ok || len(nc.nodeMetrics) != 1 {
+		t.Errorf("metrics of %v nodes, want only node-1", len(nc.nodeMetrics))
+	}
+	if _, ok := nc.lastPressure["node-1"]; !ok || len(nc.lastPressure) != 1 {
+		t.Errorf("pressure times of %v nodes, want only node-1", len(nc.lastPressure))
+	}
+}
@@ -0,0 +1,239 @@
+package dynamic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/klog/v2"
+)
+
+// DebugPath is the path of the node cache dump on the debug endpoint.
+const DebugPath = "/debug/dynamic/nodes"
+
+// debugNode is what the debug endpoint knows about a node.
+type debugNode struct {
+	Info       NodeInfo        `json:"info"`
+	Window     []debugSample   `json:"window"`
+	Thresholds debugThresholds `json:"thresholds"`
+	// Result of the pod of the ?pod= query on the node.
+	Result *debugResult `json:"result,omitempty"`
+}
+
+// debugSample is a raw usage sample of the metrics window.
+type debugSample struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Age       string          `json:"age"`
+	Usage     v1.ResourceList `json:"usage"`
+}
+
+// debugThresholds are the effective thresholds on a node, for the pod of
+// the ?pod= query if any.
+type debugThresholds struct {
+	ToleranceCPURate    float64 `json:"toleranceCPURate"`
+	ToleranceMemoryRate float64 `json:"toleranceMemoryRate"`
+	// The request rate limits, overcommit included, nil if unset.
+	RequestCPURateLimit    *float64 `json:"requestCPURateLimit,omitempty"`
+	RequestMemoryRateLimit *float64 `json:"requestMemoryRateLimit,omitempty"`
+}
+
+type debugResult struct {
+	Fits    bool    `json:"fits"`
+	Reason  string  `json:"reason,omitempty"`
+	Message string  `json:"message,omitempty"`
+	Usage   float64 `json:"usage,omitempty"`
+	Limit   float64 `json:"limit,omitempty"`
+}
+
+// debugServers are the debug and health endpoints by address, the Dynamic
+// plugins of all profiles binding the same address share one.
+var (
+	debugServersMu sync.Mutex
+	debugServers   = make(map[string]*debugServer)
+)
+
+// debugServer is a debug or health endpoint and the plugins it serves, in
+// the order of the profiles.
+type debugServer struct {
+	// debug is set when the endpoint serves the node dump besides the
+	// checks.
+	debug   bool
+	plugins []*DynamicPlugin
+	sync.RWMutex
+}
+
+func (s *debugServer) add(dp *DynamicPlugin) {
+	s.Lock()
+	defer s.Unlock()
+	s.plugins = append(s.plugins, dp)
+}
+
+// check returns the named check of newCheck failing if it fails for the
+// node cache of any plugin.
+func (s *debugServer) check(name string, newCheck func(Cache) healthz.HealthChecker) healthz.HealthChecker {
+	return healthz.NamedCheck(name, func(r *http.Request) error {
+		s.RLock()
+		defer s.RUnlock()
+		for _, dp := range s.plugins {
+			if err := newCheck(dp.NodeCache).Check(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// handleDebugNodes dumps the nodes as the plugin of the first profile sees
+// them.
+func (s *debugServer) handleDebugNodes(w http.ResponseWriter, r *http.Request) {
+	s.RLock()
+	dp := s.plugins[0]
+	s.RUnlock()
+	dp.handleDebugNodes(w, r)
+}
+
+// serveDebug serves the read-only debug endpoint, and the /healthz and
+// /readyz checks of the node cache, on address until the process exits.
+// kube-scheduler doesn't let plugins add checks to its own endpoints. The
+// plugins of later profiles on the same address only add their checks.
+func (dp *DynamicPlugin) serveDebug(address string) error {
+	return dp.serve(address, true)
+}
+
+// serveHealth serves only the /healthz and /readyz checks of the node
+// cache on address, so that the probes don't need the debug endpoint to
+// leave loopback.
+func (dp *DynamicPlugin) serveHealth(address string) error {
+	return dp.serve(address, false)
+}
+
+func (dp *DynamicPlugin) serve(address string, debug bool) error {
+	kind := "health"
+	if debug {
+		kind = "debug"
+	}
+
+	debugServersMu.Lock()
+	defer debugServersMu.Unlock()
+
+	if s, ok := debugServers[address]; ok {
+		if s.debug != debug {
+			return fmt.Errorf("%v address %v is used by another profile for another endpoint", kind, address)
+		}
+		s.add(dp)
+		klog.InfoS("Sharing Dynamic endpoint with another profile", "endpoint", kind, "address", address)
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("listen on %v address %v err: %w", kind, address, err)
+	}
+
+	s := &debugServer{debug: debug, plugins: []*DynamicPlugin{dp}}
+	mux := http.NewServeMux()
+	if debug {
+		mux.HandleFunc(DebugPath, s.handleDebugNodes)
+	}
+	healthz.InstallHandler(mux, s.check("dynamic-metrics", NewMetricsHealthCheck))
+	healthz.InstallReadyzHandler(mux, s.check("dynamic-cache-sync", NewCacheSyncCheck))
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			klog.ErrorS(err, "Dynamic endpoint stopped", "endpoint", kind, "address", address)
+		}
+	}()
+	debugServers[address] = s
+	klog.InfoS("Serving Dynamic endpoint", "endpoint", kind, "address", address)
+	return nil
+}
+
+// handleDebugNodes dumps the NodeInfo, the raw metrics window and the
+// effective thresholds of every node. With ?pod=namespace/name it also
+// evaluates Filter for that pod on every node. It runs outside of the
+// scheduling cycles, so the pods assumed on the nodes are not counted.
+func (dp *DynamicPlugin) handleDebugNodes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var pod *v1.Pod
+	if key := r.URL.Query().Get("pod"); key != "" {
+		namespace, name, ok := strings.Cut(key, "/")
+		if !ok {
+			http.Error(w, "pod must be namespace/name", http.StatusBadRequest)
+			return
+		}
+		var err error
+		pod, err = dp.handle.SharedInformerFactory().Core().V1().Pods().Lister().Pods(namespace).Get(name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+	}
+
+	nodes, err := dp.handle.SharedInformerFactory().Core().V1().Nodes().Lister().List(labels.Everything())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+
+	res := make([]debugNode, 0, len(nodes))
+	for _, node := range nodes {
+		res = append(res, dp.debugNode(r.Context(), node, pod))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(res); err != nil {
+		klog.ErrorS(err, "Failed to write debug response")
+	}
+}
+
+func (dp *DynamicPlugin) debugNode(ctx context.Context, node *v1.Node, pod *v1.Pod) debugNode {
+	info := dp.NodeCache.GetNodeInfo(node.Name, pod, nil)
+	t := dp.tolerance(klog.FromContext(ctx), pod, &info)
+	d := debugNode{
+		Info: info,
+		Thresholds: debugThresholds{
+			ToleranceCPURate:    t.cpu,
+			ToleranceMemoryRate: t.memory,
+		},
+	}
+	if limit, ok := dp.requestLimit(dp.DynamicArgs.RequestToleranceCPURate); ok {
+		d.Thresholds.RequestCPURateLimit = &limit
+	}
+	if limit, ok := dp.requestLimit(dp.DynamicArgs.RequestToleranceMemoryRate); ok {
+		d.Thresholds.RequestMemoryRateLimit = &limit
+	}
+
+	for _, m := range dp.NodeCache.GetNodeMetricsWindow(node.Name) {
+		d.Window = append(d.Window, debugSample{
+			Timestamp: m.Timestamp.Time,
+			Age:       time.Since(m.Timestamp.Time).Round(time.Second).String(),
+			Usage:     m.Usage,
+		})
+	}
+
+	if pod != nil {
+		d.Result = &debugResult{Fits: true}
+		if r := dp.evaluate(ctx, pod, node, nil); r != nil {
+			d.Result = &debugResult{Reason: r.reason, Message: r.message, Usage: r.usage, Limit: r.limit}
+		}
+	}
+	return d
+}
@@ -0,0 +1,50 @@
+package dynamic
+
+import (
+	"net"
+	"net/http"
+	"testing"
+)
+
+func freeAddress(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen err: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+// TestServeHealth checks that the health endpoint serves the checks of the
+// node cache but not the node dump, and that its address can't be reused
+// for a debug endpoint.
+func TestServeHealth(t *testing.T) {
+	address := freeAddress(t)
+	dp := &DynamicPlugin{NodeCache: &NodeCache{readiness: cacheReadiness{err: errCacheNotSynced}}}
+	if err := dp.serveHealth(address); err != nil {
+		t.Fatalf("serveHealth() err: %v", err)
+	}
+
+	for path, want := range map[string]int{
+		"/healthz": http.StatusOK,
+		"/readyz":  http.StatusInternalServerError,
+		DebugPath:  http.StatusNotFound,
+	} {
+		resp, err := http.Get("http://" + address + path)
+		if err != nil {
+			t.Fatalf("GET %v err: %v", path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("GET %v status = %v, want %v", path, resp.StatusCode, want)
+		}
+	}
+
+	if err := dp.serveHealth(address); err != nil {
+		t.Errorf("serveHealth() on a shared address err: %v", err)
+	}
+	if err := dp.serveDebug(address); err == nil {
+		t.Errorf("serveDebug() on a health address succeeded")
+	}
+}
@@ -0,0 +1,66 @@
+package dynamic
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/klog/v2"
+)
+
+// metricsHealth tracks whether the metrics provider is up.
+type metricsHealth struct {
+	// failures is the number of consecutive failed node metrics scrapes.
+	failures int32
+	degraded bool
+	since    time.Time
+	sync.RWMutex
+}
+
+// updateMetricsHealth records the outcome of a node metrics scrape. The
+// cache turns degraded after Degradation.FailureThreshold failed scrapes in
+// a row and recovers on the first successful one.
+func (nc *NodeCache) updateMetricsHealth(ok bool) {
+	nc.health.Lock()
+	defer nc.health.Unlock()
+
+	if ok {
+		nc.readiness.setScraped()
+		if nc.health.degraded {
+			klog.InfoS("Metrics provider recovered, leaving degraded mode", "degradedFor", time.Since(nc.health.since))
+		}
+		nc.health.failures = 0
+		nc.health.degraded = false
+		metricsDegraded.Set(0)
+		return
+	}
+
+	nc.health.failures++
+	if !nc.health.degraded && nc.health.failures >= nc.degradation.FailureThreshold {
+		klog.ErrorS(nil, "Metrics provider is down, entering degraded mode",
+			"failures", nc.health.failures, "policy", nc.degradation.Policy)
+		nc.health.degraded = true
+		nc.health.since = time.Now()
+		metricsDegraded.Set(1)
+	}
+}
+
+// Degraded reports whether the metrics provider is down.
+func (nc *NodeCache) Degraded() bool {
+	nc.health.RLock()
+	defer nc.health.RUnlock()
+	return nc.health.degraded
+}
+
+// NewMetricsHealthCheck returns a health check failing while the cache is
+// degraded.
+func NewMetricsHealthCheck(c Cache) healthz.HealthChecker {
+	return healthz.NamedCheck("dynamic-metrics", func(_ *http.Request) error {
+		if c.Degraded() {
+			return fmt.Errorf("metrics provider is down, the node cache is degraded")
+		}
+		return nil
+	})
+}
@@ -3,40 +3,106 @@ package dynamic
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/helper"
 
 	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
+	"github.com/tanjunchen/tanjunchen-scheduler/apis/config/validation"
 	"github.com/tanjunchen/tanjunchen-scheduler/pkg/names"
 )
 
 var _ framework.FilterPlugin = &DynamicPlugin{}
+var _ framework.PreScorePlugin = &DynamicPlugin{}
+var _ framework.ScorePlugin = &DynamicPlugin{}
+var _ framework.PostFilterPlugin = &DynamicPlugin{}
+var _ framework.ReservePlugin = &DynamicPlugin{}
+
+const preScoreStateKey = "PreScore" + names.DynamicName
 
 type DynamicPlugin struct {
 	handle      framework.Handle
 	NodeCache   Cache
 	DynamicArgs *config.DynamicArgs
+	ranker      *NodeRanker
+	overrides   []toleranceOverride
+	profiles    []nodeToleranceProfile
+	estimator   PodUsageEstimator
+	// eventLimiter rate limits the Events on pods and nodes.
+	eventLimiter *eventLimiter
+	hotState     nodeHotState
+	// shadowMu guards the creation of the shadow state of a cycle.
+	shadowMu sync.Mutex
+}
+
+// preScoreState is computed at PreScore and used at Score.
+type preScoreState struct {
+	nodeInfos map[string]NodeInfo
+	// rank of every node, 0 is the best ranked node.
+	rank map[string]int
+}
+
+// Clone the prescore state.
+func (s *preScoreState) Clone() framework.StateData {
+	return s
 }
 
 // NewDynamicPlugin initializes a new plugin and returns it.
 func NewDynamicPlugin(plArgs runtime.Object, handle framework.Handle) (framework.Plugin, error) {
 	args, ok := plArgs.(*config.DynamicArgs)
 	if !ok {
-		return nil, fmt.Errorf("want args to be of type DynamicArgs, got %T", args)
+		return nil, fmt.Errorf("want args to be of type DynamicArgs, got %T", plArgs)
+	}
+	if errs := validation.ValidateDynamicArgs(nil, args); len(errs) > 0 {
+		return nil, errs.ToAggregate()
 	}
+
+	RegisterMetrics()
+
+	overrides, err := newToleranceOverrides(args.ToleranceOverrides)
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := newNodeToleranceProfiles(args.NodeProfiles)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := handle.KubeConfig()
-	nc, err := NewNodeCache(cfg)
+	nc, err := NewNodeCache(cfg, args, handle.SharedInformerFactory())
 	if err != nil {
 		return nil, err
 	}
 
-	return &DynamicPlugin{
-		DynamicArgs: args,
-		handle:      handle,
-		NodeCache:   nc,
-	}, nil
+	dp := &DynamicPlugin{
+		DynamicArgs:  args,
+		handle:       handle,
+		NodeCache:    nc,
+		ranker:       NewNodeRanker(args.Ranking),
+		overrides:    overrides,
+		profiles:     profiles,
+		estimator:    NewPodUsageEstimator(args.PodUsageEstimation, nc),
+		eventLimiter: newEventLimiter(args.Events.Interval.Duration),
+		hotState:     nodeHotState{hot: make(map[string]bool)},
+	}
+	nc.AddScrapeHandler(dp.recordNodeEvents)
+
+	if args.Debug.BindAddress != "" {
+		if err := dp.serveDebug(args.Debug.BindAddress); err != nil {
+			return nil, err
+		}
+	}
+	if args.Debug.HealthBindAddress != "" {
+		if err := dp.serveHealth(args.Debug.HealthBindAddress); err != nil {
+			return nil, err
+		}
+	}
+	return dp, nil
 }
 
 func (dp *DynamicPlugin) Filter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
@@ -45,23 +111,267 @@ func (dp *DynamicPlugin) Filter(ctx context.Context, state *framework.CycleState
 		return framework.NewStatus(framework.Error, "node not found")
 	}
 
-	nodeName := node.Name
-	nodesStat := dp.NodeCache.GetNodeInfo(nodeName, pod)
+	r := dp.evaluate(ctx, pod, node, nodeInfo)
+	shadow := dp.DynamicArgs.Mode == config.ShadowMode
+	recordFilterResult(r, shadow)
+	if shadow {
+		dp.shadowState(state).add(node.Name, r)
+	}
+	if r == nil {
+		return framework.NewStatus(framework.Success, "")
+	}
+
+	if shadow {
+		klog.FromContext(ctx).V(3).Info("Node would be rejected by Dynamic", "pod", klog.KObj(pod), "node", klog.KObj(node),
+			"reason", r.reason, "rate", r.rate, "usage", r.usage, "limit", r.limit)
+		return framework.NewStatus(framework.Success, "")
+	}
+
+	klog.FromContext(ctx).V(4).Info("Node rejected by Dynamic", "pod", klog.KObj(pod), "node", klog.KObj(node),
+		"reason", r.reason, "rate", r.rate, "usage", r.usage, "limit", r.limit)
+	return framework.NewStatus(framework.Unschedulable, r.message)
+}
+
+// evaluate runs the dynamic checks of Filter, it returns nil if the pod
+// fits the node. nodeInfo is the NodeInfo of the scheduling cycle, nil
+// outside of it.
+func (dp *DynamicPlugin) evaluate(ctx context.Context, pod *v1.Pod, node *v1.Node, nodeInfo *framework.NodeInfo) *rejection {
+	nodesStat := dp.NodeCache.GetNodeInfo(node.Name, pod, nodeInfo)
+
+	klog.FromContext(ctx).V(5).Info("Node rates", "pod", klog.KObj(pod), "node", klog.KObj(node),
+		"realCPU", nodesStat.RealCPURate, "requestCPU", nodesStat.RequestCPURate,
+		"realMemory", nodesStat.RealMemoryRate, "requestMemory", nodesStat.RequestMemoryRate, "stale", nodesStat.Stale)
+
+	if dp.DynamicArgs.NodePressure.Policy == config.RejectNodePressure && dp.underPressure(&nodesStat) {
+		return &rejection{reason: reasonNodePressure, message: pressureReason(&nodesStat)}
+	}
+
+	if limit, ok := dp.requestLimit(dp.DynamicArgs.RequestToleranceCPURate); ok && nodesStat.RequestCPURate > limit {
+		return newRateRejection(reasonRequestCPU, "request cpu", nodesStat.RequestCPURate, limit)
+	}
+
+	if limit, ok := dp.requestLimit(dp.DynamicArgs.RequestToleranceMemoryRate); ok && nodesStat.RequestMemoryRate > limit {
+		return newRateRejection(reasonRequestMemory, "request memory", nodesStat.RequestMemoryRate, limit)
+	}
+
+	for _, r := range dp.DynamicArgs.Resources {
+		rate, ok := nodesStat.Resources[r.Name]
+		if !ok {
+			continue
+		}
+		if r.RequestToleranceRate != nil && rate.Request > *r.RequestToleranceRate {
+			return newRateRejection(reasonRequestResource, "request "+string(r.Name), rate.Request, *r.RequestToleranceRate)
+		}
+		if r.ToleranceRate != nil && rate.HasReal && rate.Real > *r.ToleranceRate {
+			return newRateRejection(reasonRealResource, "real "+string(r.Name), rate.Real, *r.ToleranceRate)
+		}
+	}
+
+	if nodesStat.Degraded {
+		if dp.DynamicArgs.Degradation.Policy == config.PassThroughDegradation {
+			return nil
+		}
+	} else if nodesStat.Stale {
+		switch dp.DynamicArgs.Staleness.Policy {
+		case config.RejectStaleMetrics:
+			return &rejection{reason: reasonStaleMetrics, message: "Node metrics are missing or stale"}
+		case config.AdmitStaleMetrics:
+			return nil
+		}
+	}
+
+	t := dp.tolerance(klog.FromContext(ctx), pod, &nodesStat)
+	cpuRate, memoryRate := nodesStat.RealCPURate, nodesStat.RealMemoryRate
+	// Stale request based rates already hold the requests of the pod.
+	if !nodesStat.Stale {
+		podCPURate, podMemoryRate := dp.estimatePodRates(pod, node)
+		cpuRate += podCPURate
+		memoryRate += podMemoryRate
+	}
+
+	if cpuRate > t.cpu {
+		return newRateRejection(reasonRealCPU, "real cpu", cpuRate, t.cpu)
+	}
+
+	if memoryRate > t.memory {
+		return newRateRejection(reasonRealMemory, "real memory", memoryRate, t.memory)
+	}
+
+	return nil
+}
+
+// estimatePodRates returns the expected usage of the pod in percent of the
+// node capacity, the same base as the real rates of the node.
+func (dp *DynamicPlugin) estimatePodRates(pod *v1.Pod, node *v1.Node) (cpuRate, memoryRate float64) {
+	usage := dp.estimator.EstimatePodUsage(pod)
+	if usage == nil {
+		return 0, 0
+	}
+
+	if capacity := node.Status.Capacity.Cpu().MilliValue(); capacity > 0 {
+		cpuRate = 100 * float64(usage.Cpu().MilliValue()) / float64(capacity)
+	}
+	if capacity := node.Status.Capacity.Memory().Value(); capacity > 0 {
+		memoryRate = 100 * float64(usage.Memory().Value()) / float64(capacity)
+	}
+	return cpuRate, memoryRate
+}
+
+// underPressure reports whether the node is under pressure or was within
+// the pressure cooldown.
+func (dp *DynamicPlugin) underPressure(info *NodeInfo) bool {
+	if len(info.Pressure) > 0 {
+		return true
+	}
+	return !info.LastPressure.IsZero() && time.Since(info.LastPressure) < dp.DynamicArgs.NodePressure.Cooldown.Duration
+}
+
+func pressureReason(info *NodeInfo) string {
+	if len(info.Pressure) > 0 {
+		return fmt.Sprintf("Node has %v", info.Pressure[0])
+	}
+	return "Node had pressure within cooldown"
+}
+
+// requestLimit returns the request rate ceiling for a request tolerance,
+// ok is false when the tolerance is unset.
+func (dp *DynamicPlugin) requestLimit(tolerance *float64) (limit float64, ok bool) {
+	if tolerance == nil {
+		return 0, false
+	}
+	return *tolerance * dp.DynamicArgs.OvercommitRatio, true
+}
+
+// PreScore ranks all feasible nodes so that Score can reward the best
+// ranked ones.
+func (dp *DynamicPlugin) PreScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodes []*v1.Node) *framework.Status {
+	nodeInfos := make([]*framework.NodeInfo, len(nodes))
+	for i, n := range nodes {
+		nodeInfo, err := dp.handle.SnapshotSharedLister().NodeInfos().Get(n.Name)
+		if err != nil {
+			return framework.AsStatus(fmt.Errorf("getting node %q from Snapshot: %w", n.Name, err))
+		}
+		nodeInfos[i] = nodeInfo
+	}
 
-	fmt.Printf("node name: %s, node real cpu: %f, node request cpu: %f, node real memory: %f, node request memory %f\n",
-		nodesStat.NodeName, nodesStat.RealCPURate, nodesStat.RequestCPURate, nodesStat.RealMemoryRate, nodesStat.RequestMemoryRate)
+	infos := dp.NodeCache.GetNodeInfos(nodeInfos, pod)
+	dp.ranker.Sort(infos)
+
+	s := &preScoreState{
+		nodeInfos: make(map[string]NodeInfo, len(infos)),
+		rank:      make(map[string]int, len(infos)),
+	}
+	for i, info := range infos {
+		s.nodeInfos[info.NodeName] = info
+		s.rank[info.NodeName] = i
+	}
+	state.Write(preScoreStateKey, s)
+	return nil
+}
+
+// Score combines the real cpu and memory usage of the node, weighted by
+// CPUWeight and MemoryWeight, with its rank computed at PreScore.
+func (dp *DynamicPlugin) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
+	s, err := getPreScoreState(state)
+	if err != nil {
+		klog.FromContext(ctx).V(4).Info("Scoring node on usage only", "pod", klog.KObj(pod), "node", nodeName, "err", err)
+	}
+
+	nodesStat, ok := s.nodeInfos[nodeName]
+	if !ok {
+		nodeInfo, err := dp.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+		if err != nil {
+			return 0, framework.AsStatus(fmt.Errorf("getting node %q from Snapshot: %w", nodeName, err))
+		}
+		nodesStat = dp.NodeCache.GetNodeInfo(nodeName, pod, nodeInfo)
+	}
+
+	cpuWeight, memoryWeight := dp.DynamicArgs.CPUWeight, dp.DynamicArgs.MemoryWeight
+	cpuScore, memoryScore := leastUsedScore(nodesStat.RealCPURate), leastUsedScore(nodesStat.RealMemoryRate)
+	if dp.DynamicArgs.Ranking.Direction == config.BinPackRanking {
+		cpuScore, memoryScore = framework.MaxNodeScore-cpuScore, framework.MaxNodeScore-memoryScore
+	}
 
-	if nodesStat.RealCPURate > dp.DynamicArgs.ToleranceCPURate {
-		fmt.Printf("node name: %s, node real cpu rate > %v\n", nodesStat.NodeName, dp.DynamicArgs.ToleranceCPURate)
-		return framework.NewStatus(framework.Unschedulable, fmt.Sprintf("Real cpu rate > %v", dp.DynamicArgs.ToleranceCPURate))
+	score := cpuScore*cpuWeight + memoryScore*memoryWeight
+	weight := cpuWeight + memoryWeight
+	if rank, ok := s.rank[nodeName]; ok {
+		score += rankScore(rank, len(s.rank)) * dp.DynamicArgs.Ranking.Weight
+		weight += dp.DynamicArgs.Ranking.Weight
 	}
 
-	if nodesStat.RealMemoryRate > dp.DynamicArgs.ToleranceMemoryRate {
-		fmt.Printf("node name: %s, node real memory rate > %v\n", nodesStat.NodeName, dp.DynamicArgs.ToleranceMemoryRate)
-		return framework.NewStatus(framework.Unschedulable, fmt.Sprintf("Real memory rate > %v", dp.DynamicArgs.ToleranceMemoryRate))
+	if weight == 0 {
+		return 0, nil
 	}
+	score /= weight
 
-	return framework.NewStatus(framework.Success, "")
+	if dp.DynamicArgs.NodePressure.Policy == config.PenalizeNodePressure && dp.underPressure(&nodesStat) {
+		score -= dp.DynamicArgs.NodePressure.Penalty
+		if score < framework.MinNodeScore {
+			score = framework.MinNodeScore
+		}
+	}
+	return score, nil
+}
+
+// Reserve records the Shadow mode decision of the pod, which got a node,
+// and clears the diagnosis PostFilter left on it in an earlier cycle.
+func (dp *DynamicPlugin) Reserve(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) *framework.Status {
+	if dp.DynamicArgs.Mode == config.ShadowMode {
+		dp.recordShadowDecision(ctx, state, pod)
+	}
+	dp.clearDiagnosis(ctx, pod)
+	return nil
+}
+
+// Unreserve of the Reserve plugin, there is nothing to undo.
+func (dp *DynamicPlugin) Unreserve(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) {
+}
+
+// ScoreExtensions of the Score plugin.
+func (dp *DynamicPlugin) ScoreExtensions() framework.ScoreExtensions {
+	return dp
+}
+
+// NormalizeScore scales the scores so that the best node gets
+// MaxNodeScore. Scores keep their proportions, a stretch from the lowest
+// to the highest score would turn a one point difference in usage into
+// the full score range.
+func (dp *DynamicPlugin) NormalizeScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, scores framework.NodeScoreList) *framework.Status {
+	return helper.DefaultNormalizeScore(framework.MaxNodeScore, false, scores)
+}
+
+// leastUsedScore maps a usage rate in percent to a node score, the lower
+// the usage the higher the score.
+func leastUsedScore(rate float64) int64 {
+	if rate >= 100 {
+		return framework.MinNodeScore
+	}
+	if rate <= 0 {
+		return framework.MaxNodeScore
+	}
+	return int64((100 - rate) * float64(framework.MaxNodeScore) / 100)
+}
+
+// rankScore maps the rank of a node among total nodes to a node score,
+// the best ranked node gets MaxNodeScore.
+func rankScore(rank, total int) int64 {
+	if total <= 1 {
+		return framework.MaxNodeScore
+	}
+	return framework.MaxNodeScore * int64(total-1-rank) / int64(total-1)
+}
+
+func getPreScoreState(state *framework.CycleState) (*preScoreState, error) {
+	c, err := state.Read(preScoreStateKey)
+	if err != nil {
+		return &preScoreState{}, fmt.Errorf("reading %q from cycleState: %w", preScoreStateKey, err)
+	}
+
+	s, ok := c.(*preScoreState)
+	if !ok {
+		return &preScoreState{}, fmt.Errorf("invalid PreScore state, got type %T", c)
+	}
+	return s, nil
 }
 
 func (dp *DynamicPlugin) Name() string {
@@ -0,0 +1,91 @@
+package dynamic
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
+)
+
+func TestScoreNormalizeScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		ranking config.NodeRankingArgs
+		infos   []NodeInfo
+		rank    map[string]int
+		want    map[string]int64
+	}{
+		{
+			name: "close usage keeps close scores",
+			infos: []NodeInfo{
+				{NodeName: "a", RealCPURate: 40, RealMemoryRate: 40},
+				{NodeName: "b", RealCPURate: 41, RealMemoryRate: 41},
+				{NodeName: "c", RealCPURate: 70, RealMemoryRate: 70},
+			},
+			want: map[string]int64{"a": 100, "b": 98, "c": 50},
+		},
+		{
+			name:    "binpack",
+			ranking: config.NodeRankingArgs{Direction: config.BinPackRanking},
+			infos: []NodeInfo{
+				{NodeName: "a", RealCPURate: 40, RealMemoryRate: 40},
+				{NodeName: "b", RealCPURate: 80, RealMemoryRate: 80},
+			},
+			want: map[string]int64{"a": 50, "b": 100},
+		},
+		{
+			name:    "rank",
+			ranking: config.NodeRankingArgs{Direction: config.SpreadRanking, Weight: 2},
+			infos: []NodeInfo{
+				{NodeName: "a", RealCPURate: 40, RealMemoryRate: 40},
+				{NodeName: "b", RealCPURate: 41, RealMemoryRate: 41},
+			},
+			// (60+60+100*2)/4 and (59+59+0)/4.
+			rank: map[string]int{"a": 0, "b": 1},
+			want: map[string]int64{"a": 100, "b": 36},
+		},
+		{
+			name: "all nodes full",
+			infos: []NodeInfo{
+				{NodeName: "a", RealCPURate: 100, RealMemoryRate: 100},
+				{NodeName: "b", RealCPURate: 120, RealMemoryRate: 100},
+			},
+			want: map[string]int64{"a": 0, "b": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dp := &DynamicPlugin{
+				DynamicArgs: &config.DynamicArgs{CPUWeight: 1, MemoryWeight: 1, Ranking: tt.ranking},
+			}
+			s := &preScoreState{nodeInfos: make(map[string]NodeInfo), rank: tt.rank}
+			for _, info := range tt.infos {
+				s.nodeInfos[info.NodeName] = info
+			}
+			state := framework.NewCycleState()
+			state.Write(preScoreStateKey, s)
+
+			ctx := context.Background()
+			var scores framework.NodeScoreList
+			for _, info := range tt.infos {
+				score, status := dp.Score(ctx, state, nil, info.NodeName)
+				if !status.IsSuccess() {
+					t.Fatalf("Score(%v) status = %v", info.NodeName, status)
+				}
+				scores = append(scores, framework.NodeScore{Name: info.NodeName, Score: score})
+			}
+			if status := dp.NormalizeScore(ctx, state, nil, scores); !status.IsSuccess() {
+				t.Fatalf("NormalizeScore() status = %v", status)
+			}
+
+			for _, score := range scores {
+				if score.Score != tt.want[score.Name] {
+					t.Errorf("node %v score = %v, want %v", score.Name, score.Score, tt.want[score.Name])
+				}
+			}
+		})
+	}
+}
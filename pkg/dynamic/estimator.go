@@ -0,0 +1,76 @@
+package dynamic
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	resourcehelper "k8s.io/kubernetes/pkg/api/v1/resource"
+
+	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
+)
+
+// PodUsageEstimator estimates the real usage of a pod before it runs.
+type PodUsageEstimator interface {
+	// EstimatePodUsage returns the expected usage of the pod, nil when
+	// nothing is expected.
+	EstimatePodUsage(pod *v1.Pod) v1.ResourceList
+}
+
+// NewPodUsageEstimator new pod usage estimator of the configured type
+func NewPodUsageEstimator(args config.PodUsageEstimationArgs, c Cache) PodUsageEstimator {
+	switch args.Estimator {
+	case config.HistoryEstimator:
+		return &historyEstimator{
+			cache:     c,
+			statistic: args.HistoryStatistic,
+			fallback:  &ratioEstimator{ratio: args.UsageRequestRatio},
+		}
+	case config.RequestEstimator:
+		return &ratioEstimator{ratio: 1}
+	case config.RatioEstimator:
+		return &ratioEstimator{ratio: args.UsageRequestRatio}
+	}
+	return noneEstimator{}
+}
+
+type noneEstimator struct{}
+
+func (noneEstimator) EstimatePodUsage(pod *v1.Pod) v1.ResourceList {
+	return nil
+}
+
+// ratioEstimator expects a pod to use a ratio of its requests.
+type ratioEstimator struct {
+	ratio float64
+}
+
+func (e *ratioEstimator) EstimatePodUsage(pod *v1.Pod) v1.ResourceList {
+	reqs, _ := resourcehelper.PodRequestsAndLimits(pod)
+	return v1.ResourceList{
+		v1.ResourceCPU:    *resource.NewMilliQuantity(int64(float64(reqs.Cpu().MilliValue())*e.ratio), resource.DecimalSI),
+		v1.ResourceMemory: *resource.NewQuantity(int64(float64(reqs.Memory().Value())*e.ratio), resource.BinarySI),
+	}
+}
+
+// historyEstimator expects a pod to use what the replicas of its workload
+// use, and falls back to a ratio of its requests for new workloads.
+type historyEstimator struct {
+	cache     Cache
+	statistic config.HistoryStatistic
+	fallback  PodUsageEstimator
+}
+
+func (e *historyEstimator) EstimatePodUsage(pod *v1.Pod) v1.ResourceList {
+	profile, ok := e.cache.GetWorkloadProfile(pod)
+	if !ok {
+		return e.fallback.EstimatePodUsage(pod)
+	}
+
+	cpu, memory := profile.PeakCPU, profile.PeakMemory
+	if e.statistic == config.MeanHistoryStatistic {
+		cpu, memory = profile.MeanCPU, profile.MeanMemory
+	}
+	return v1.ResourceList{
+		v1.ResourceCPU:    *resource.NewMilliQuantity(cpu, resource.DecimalSI),
+		v1.ResourceMemory: *resource.NewQuantity(memory, resource.BinarySI),
+	}
+}
@@ -0,0 +1,117 @@
+package dynamic
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+// eventLimiter lets one Event per key through every interval.
+type eventLimiter struct {
+	interval  time.Duration
+	last      map[string]time.Time
+	lastPrune time.Time
+	sync.Mutex
+}
+
+func newEventLimiter(interval time.Duration) *eventLimiter {
+	return &eventLimiter{
+		interval: interval,
+		last:     make(map[string]time.Time),
+	}
+}
+
+// allow reports whether an Event on key may be emitted now, and if so
+// records it.
+func (l *eventLimiter) allow(key string) bool {
+	l.Lock()
+	defer l.Unlock()
+
+	now := time.Now()
+	// The keys of objects that went away are dropped once they expire.
+	if now.Sub(l.lastPrune) > l.interval {
+		for k, t := range l.last {
+			if now.Sub(t) >= l.interval {
+				delete(l.last, k)
+			}
+		}
+		l.lastPrune = now
+	}
+
+	if t, ok := l.last[key]; ok && now.Sub(t) < l.interval {
+		return false
+	}
+	l.last[key] = now
+	return true
+}
+
+// nodeHotState tracks which nodes are hot.
+type nodeHotState struct {
+	hot map[string]bool
+	sync.Mutex
+}
+
+// hotReason returns why the node is hot, or "" if it is not: it is under
+// pressure, or its real usage is over its tolerance.
+func (dp *DynamicPlugin) hotReason(info *NodeInfo) string {
+	if dp.underPressure(info) {
+		return pressureReason(info)
+	}
+
+	t := dp.tolerance(klog.Background(), nil, info)
+	if info.RealCPURate > t.cpu {
+		return fmt.Sprintf("real cpu rate %.1f > %v", info.RealCPURate, t.cpu)
+	}
+	if info.RealMemoryRate > t.memory {
+		return fmt.Sprintf("real memory rate %.1f > %v", info.RealMemoryRate, t.memory)
+	}
+	return ""
+}
+
+// recordNodeEvents emits an Event on every node turning hot or cooling
+// down since the last scrape. Nodes seen for the first time and nodes with
+// stale metrics don't change state.
+func (dp *DynamicPlugin) recordNodeEvents(infos NodeInfos) {
+	dp.hotState.Lock()
+	defer dp.hotState.Unlock()
+
+	seen := make(map[string]struct{}, len(infos))
+	for i := range infos {
+		info := &infos[i]
+		seen[info.NodeName] = struct{}{}
+		if info.Stale {
+			continue
+		}
+
+		reason := dp.hotReason(info)
+		wasHot, known := dp.hotState.hot[info.NodeName]
+		dp.hotState.hot[info.NodeName] = reason != ""
+		if !known || wasHot == (reason != "") {
+			continue
+		}
+
+		klog.V(3).InfoS("Node hot state changed", "node", klog.KRef("", info.NodeName), "hot", reason != "", "reason", reason)
+		if !dp.eventLimiter.allow("node/" + info.NodeName) {
+			continue
+		}
+		// Nodes are referenced by name, as the kubelet does.
+		ref := &v1.ObjectReference{Kind: "Node", Name: info.NodeName, UID: types.UID(info.NodeName)}
+		if reason != "" {
+			dp.handle.EventRecorder().Eventf(ref, nil, v1.EventTypeWarning, "NodeHot", "DynamicScheduling",
+				"Node is hot for dynamic scheduling: %s", reason)
+		} else {
+			dp.handle.EventRecorder().Eventf(ref, nil, v1.EventTypeNormal, "NodeCooledDown", "DynamicScheduling",
+				"Node is no longer hot for dynamic scheduling")
+		}
+	}
+
+	for name := range dp.hotState.hot {
+		if _, ok := seen[name]; !ok {
+			delete(dp.hotState.hot, name)
+		}
+	}
+}
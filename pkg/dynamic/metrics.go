@@ -0,0 +1,164 @@
+package dynamic
+
+import (
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const dynamicSubsystem = "dynamic_scheduler"
+
+// Scrape targets of the scrape metrics.
+const (
+	nodeScrapeTarget = "node"
+	podScrapeTarget  = "pod"
+)
+
+var (
+	filterResults = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      dynamicSubsystem,
+			Name:           "filter_results_total",
+			Help:           "Number of Filter results of the Dynamic plugin by result and rejection reason.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"result", "reason"})
+
+	nodeUsageRate = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      dynamicSubsystem,
+			Name:           "node_usage_rate",
+			Help:           "Cached usage rate of the nodes in percent, real over capacity or requests over allocatable.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"node", "resource", "type"})
+
+	nodeMetricsAge = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      dynamicSubsystem,
+			Name:           "node_metrics_age_seconds",
+			Help:           "Age of the latest usage sample of the nodes.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"node"})
+
+	scrapeDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      dynamicSubsystem,
+			Name:           "scrape_duration_seconds",
+			Help:           "Duration of the scrapes of the metrics provider.",
+			Buckets:        metrics.ExponentialBuckets(0.01, 2, 12),
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"target"})
+
+	scrapeErrors = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      dynamicSubsystem,
+			Name:           "scrape_errors_total",
+			Help:           "Number of failed requests to the metrics provider.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"target"})
+
+	metricsDegraded = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Subsystem:      dynamicSubsystem,
+			Name:           "metrics_degraded",
+			Help:           "1 while the metrics provider is down and the node cache is degraded, else 0.",
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	cacheSize = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      dynamicSubsystem,
+			Name:           "cache_size",
+			Help:           "Number of entries of the node cache: nodes with samples, samples and workload profiles.",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"cache"})
+
+	registerMetrics sync.Once
+)
+
+// RegisterMetrics registers the metrics of the Dynamic plugin with the
+// scheduler's /metrics endpoint.
+func RegisterMetrics() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(filterResults)
+		legacyregistry.MustRegister(nodeUsageRate)
+		legacyregistry.MustRegister(nodeMetricsAge)
+		legacyregistry.MustRegister(scrapeDuration)
+		legacyregistry.MustRegister(scrapeErrors)
+		legacyregistry.MustRegister(cacheSize)
+		legacyregistry.MustRegister(metricsDegraded)
+	})
+}
+
+// recordFilterResult counts a Filter result, r is nil when the node fits.
+// The rejections ignored in Shadow mode are counted apart.
+func recordFilterResult(r *rejection, shadow bool) {
+	switch {
+	case r == nil:
+		filterResults.WithLabelValues("success", "").Inc()
+	case shadow:
+		filterResults.WithLabelValues("shadow_unschedulable", r.reason).Inc()
+	default:
+		filterResults.WithLabelValues("unschedulable", r.reason).Inc()
+	}
+}
+
+// recordNodeInfo exports the cached rates of a node.
+func recordNodeInfo(info *NodeInfo) {
+	nodeUsageRate.WithLabelValues(info.NodeName, string(corev1.ResourceCPU), "real").Set(info.RealCPURate)
+	nodeUsageRate.WithLabelValues(info.NodeName, string(corev1.ResourceCPU), "request").Set(info.RequestCPURate)
+	nodeUsageRate.WithLabelValues(info.NodeName, string(corev1.ResourceMemory), "real").Set(info.RealMemoryRate)
+	nodeUsageRate.WithLabelValues(info.NodeName, string(corev1.ResourceMemory), "request").Set(info.RequestMemoryRate)
+	for name, rate := range info.Resources {
+		if rate.HasReal {
+			nodeUsageRate.WithLabelValues(info.NodeName, string(name), "real").Set(rate.Real)
+		}
+		nodeUsageRate.WithLabelValues(info.NodeName, string(name), "request").Set(rate.Request)
+	}
+
+	if !info.MetricsTimestamp.IsZero() {
+		nodeMetricsAge.WithLabelValues(info.NodeName).Set(time.Since(info.MetricsTimestamp).Seconds())
+	}
+}
+
+// forgetNode drops the series of a deleted node.
+func (nc *NodeCache) forgetNode(nodeName string) {
+	resources := append([]corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}, nc.resources...)
+	for _, name := range resources {
+		for _, rateType := range []string{"real", "request"} {
+			nodeUsageRate.Delete(map[string]string{"node": nodeName, "resource": string(name), "type": rateType})
+		}
+	}
+	nodeMetricsAge.Delete(map[string]string{"node": nodeName})
+}
+
+// recordCache exports the node cache sizes and the cached rates of the
+// nodes, excluding any incoming or assumed pod, and hands the rates to the
+// scrape handlers.
+func (nc *NodeCache) recordCache(nodes []*corev1.Node) {
+	nc.RLock()
+	samples := 0
+	for _, l := range nc.nodeMetrics {
+		samples += l.Len()
+	}
+	cacheSize.WithLabelValues("nodes").Set(float64(len(nc.nodeMetrics)))
+	cacheSize.WithLabelValues("samples").Set(float64(samples))
+	handlers := nc.scrapeHandlers
+	nc.RUnlock()
+
+	nc.profiles.RLock()
+	cacheSize.WithLabelValues("workloads").Set(float64(len(nc.profiles.samples)))
+	nc.profiles.RUnlock()
+
+	infos := make(NodeInfos, 0, len(nodes))
+	for _, node := range nodes {
+		info := nc.GetNodeInfo(node.Name, nil, nil)
+		recordNodeInfo(&info)
+		infos = append(infos, info)
+	}
+	for _, handler := range handlers {
+		handler(infos)
+	}
+}
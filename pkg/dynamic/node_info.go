@@ -2,50 +2,115 @@ package dynamic
 
 import (
 	"math"
+	"sort"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+
+	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
 )
 
 type NodeInfo struct {
-	NodeName    string
-	Labels      map[string]string
-	Annotations map[string]string
+	NodeName    string            `json:"nodeName"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	RealCPURate          float64           `json:"realCPURate"`
+	RequestCPURate       float64           `json:"requestCPURate"`
+	RemainAllocatableCPU resource.Quantity `json:"remainAllocatableCPU"`
+
+	RealMemoryRate          float64           `json:"realMemoryRate"`
+	RequestMemoryRate       float64           `json:"requestMemoryRate"`
+	RemainAllocatableMemory resource.Quantity `json:"remainAllocatableMemory"`
+
+	// Resources are the rates of the extra resources the node has.
+	Resources map[corev1.ResourceName]ResourceRate `json:"resources,omitempty"`
 
-	RealCPURate          float64
-	RequestCPURate       float64
-	RemainAllocatableCPU resource.Quantity
+	// Pressure are the pressure conditions the node is under.
+	Pressure []corev1.NodeConditionType `json:"pressure,omitempty"`
+	// LastPressure is the last time the node was seen under pressure.
+	LastPressure time.Time `json:"lastPressure"`
+
+	// MetricsTimestamp is the time of the latest usage sample.
+	MetricsTimestamp time.Time `json:"metricsTimestamp"`
+	// Stale is set when the node has no usage sample younger than the
+	// max age, the real rates then follow the stale metrics policy.
+	Stale bool `json:"stale"`
+	// Degraded is set when the metrics provider is down, or the cache is
+	// not ready yet, and the node has no usage sample younger than the max
+	// age. The real rates are then the last known good ones if any, else
+	// the request rates.
+	Degraded bool `json:"degraded"`
+}
+
+// ResourceRate is the usage of a resource in percent.
+type ResourceRate struct {
+	// Real is the real usage over capacity, only set if HasReal.
+	Real    float64 `json:"real"`
+	HasReal bool    `json:"hasReal"`
+	// Request is the requests over allocatable.
+	Request float64 `json:"request"`
+}
 
-	RealMemoryRate          float64
-	RequestMemoryRate       float64
-	RemainAllocatableMemory resource.Quantity
+// Rate returns the usage rate of the given ranking dimension.
+func (info *NodeInfo) Rate(dimension config.RankingDimension) float64 {
+	switch dimension {
+	case config.RealCPURanking:
+		return info.RealCPURate
+	case config.RealMemoryRanking:
+		return info.RealMemoryRate
+	case config.RequestCPURanking:
+		return info.RequestCPURate
+	case config.RequestMemoryRanking:
+		return info.RequestMemoryRate
+	}
+	return 0
 }
 
 type NodeInfos []NodeInfo
 
-// 1.RealMemoryRate
-// 2.RealCPURate
-// 3.RequestMemoryRate
-// 4.RequestCPURate
-func (s NodeInfos) Less(i, j int) bool {
-	e := 5.0
-	if math.Abs(float64(s[i].RealMemoryRate-s[j].RealMemoryRate)) < e {
-		if math.Abs(float64(s[i].RealCPURate-s[j].RealCPURate)) < e {
-			if math.Abs(float64(s[i].RequestMemoryRate-s[j].RealMemoryRate)) < e {
-				return s[i].RequestCPURate > s[j].RequestCPURate
-			}
-			return s[i].RequestMemoryRate > s[j].RequestMemoryRate
+// NodeRanker orders nodes by the configured ranking rules. Rates are
+// compared by their Epsilon wide bucket rather than by their distance,
+// so the ordering stays transitive, and node names break the last tie.
+type NodeRanker struct {
+	direction config.RankingDirection
+	rules     []config.RankingRule
+}
+
+// NewNodeRanker new node ranker
+func NewNodeRanker(args config.NodeRankingArgs) *NodeRanker {
+	return &NodeRanker{
+		direction: args.Direction,
+		rules:     args.Rules,
+	}
+}
+
+// Less reports whether node a ranks before node b.
+func (r *NodeRanker) Less(a, b *NodeInfo) bool {
+	for _, rule := range r.rules {
+		ba, bb := bucket(a.Rate(rule.Dimension), rule.Epsilon), bucket(b.Rate(rule.Dimension), rule.Epsilon)
+		if ba == bb {
+			continue
 		}
-		return s[i].RealCPURate > s[j].RealCPURate
+		if r.direction == config.BinPackRanking {
+			return ba > bb
+		}
+		return ba < bb
 	}
-	return s[i].RealMemoryRate > s[j].RealMemoryRate
+	return a.NodeName < b.NodeName
 }
 
-// Swap s
-func (s NodeInfos) Swap(i, j int) {
-	s[i], s[j] = s[j], s[i]
+// Sort sorts infos from the best ranked node to the worst.
+func (r *NodeRanker) Sort(infos NodeInfos) {
+	sort.Slice(infos, func(i, j int) bool {
+		return r.Less(&infos[i], &infos[j])
+	})
 }
 
-// Len l
-func (s NodeInfos) Len() int {
-	return len(s)
+func bucket(rate, epsilon float64) float64 {
+	if epsilon <= 0 {
+		return rate
+	}
+	return math.Floor(rate / epsilon)
 }
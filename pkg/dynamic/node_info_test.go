@@ -0,0 +1,248 @@
+package dynamic
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
+)
+
+func cpuRule(epsilon float64) config.RankingRule {
+	return config.RankingRule{Dimension: config.RealCPURanking, Epsilon: epsilon}
+}
+
+func TestNodeRankerLess(t *testing.T) {
+	tests := []struct {
+		name      string
+		direction config.RankingDirection
+		rules     []config.RankingRule
+		a, b      NodeInfo
+		want      bool
+	}{
+		{
+			name:      "spread prefers the lower bucket",
+			direction: config.SpreadRanking,
+			rules:     []config.RankingRule{cpuRule(5)},
+			a:         NodeInfo{NodeName: "b", RealCPURate: 10},
+			b:         NodeInfo{NodeName: "a", RealCPURate: 20},
+			want:      true,
+		},
+		{
+			name:      "binpack prefers the higher bucket",
+			direction: config.BinPackRanking,
+			rules:     []config.RankingRule{cpuRule(5)},
+			a:         NodeInfo{NodeName: "b", RealCPURate: 10},
+			b:         NodeInfo{NodeName: "a", RealCPURate: 20},
+			want:      false,
+		},
+		{
+			name:      "same bucket falls back to the node name",
+			direction: config.SpreadRanking,
+			rules:     []config.RankingRule{cpuRule(5)},
+			a:         NodeInfo{NodeName: "b", RealCPURate: 5.1},
+			b:         NodeInfo{NodeName: "a", RealCPURate: 9.9},
+			want:      false,
+		},
+		{
+			name:      "rates across a bucket boundary differ",
+			direction: config.SpreadRanking,
+			rules:     []config.RankingRule{cpuRule(5)},
+			a:         NodeInfo{NodeName: "b", RealCPURate: 4.9},
+			b:         NodeInfo{NodeName: "a", RealCPURate: 5.1},
+			want:      true,
+		},
+		{
+			name:      "same bucket goes to the next rule",
+			direction: config.SpreadRanking,
+			rules: []config.RankingRule{
+				cpuRule(5),
+				{Dimension: config.RealMemoryRanking, Epsilon: 5},
+			},
+			a:    NodeInfo{NodeName: "b", RealCPURate: 11, RealMemoryRate: 30},
+			b:    NodeInfo{NodeName: "a", RealCPURate: 12, RealMemoryRate: 60},
+			want: true,
+		},
+		{
+			name:      "zero epsilon compares the raw rates",
+			direction: config.SpreadRanking,
+			rules:     []config.RankingRule{cpuRule(0)},
+			a:         NodeInfo{NodeName: "b", RealCPURate: 11},
+			b:         NodeInfo{NodeName: "a", RealCPURate: 12},
+			want:      true,
+		},
+		{
+			name:      "equal nodes are not less",
+			direction: config.BinPackRanking,
+			rules:     []config.RankingRule{cpuRule(5)},
+			a:         NodeInfo{NodeName: "a", RealCPURate: 10},
+			b:         NodeInfo{NodeName: "a", RealCPURate: 10},
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewNodeRanker(config.NodeRankingArgs{Direction: tt.direction, Rules: tt.rules})
+			if got := r.Less(&tt.a, &tt.b); got != tt.want {
+				t.Errorf("Less() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNodeRankerStrictWeakOrdering checks irreflexivity, asymmetry and
+// transitivity of Less on rates spread around the bucket boundaries, where
+// comparing by distance would break transitivity.
+func TestNodeRankerStrictWeakOrdering(t *testing.T) {
+	rates := []float64{0, 2.4, 4.9, 5, 5.1, 7.4, 9.9, 10, 10.1, 14.9}
+	infos := make([]NodeInfo, 0, len(rates)*2)
+	for i, rate := range rates {
+		infos = append(infos,
+			NodeInfo{NodeName: string(rune('a' + i)), RealCPURate: rate, RealMemoryRate: 100 - rate},
+			NodeInfo{NodeName: string(rune('A' + i)), RealCPURate: rate, RealMemoryRate: rate},
+		)
+	}
+
+	for _, direction := range []config.RankingDirection{config.SpreadRanking, config.BinPackRanking} {
+		r := NewNodeRanker(config.NodeRankingArgs{
+			Direction: direction,
+			Rules: []config.RankingRule{
+				cpuRule(5),
+				{Dimension: config.RealMemoryRanking, Epsilon: 5},
+			},
+		})
+		for i := range infos {
+			a := &infos[i]
+			if r.Less(a, a) {
+				t.Errorf("%v: Less(%v, %v) is true", direction, a.NodeName, a.NodeName)
+			}
+			for j := range infos {
+				b := &infos[j]
+				if r.Less(a, b) && r.Less(b, a) {
+					t.Errorf("%v: Less(%v, %v) and Less(%v, %v) are both true", direction, a.NodeName, b.NodeName, b.NodeName, a.NodeName)
+				}
+				for k := range infos {
+					c := &infos[k]
+					if r.Less(a, b) && r.Less(b, c) && !r.Less(a, c) {
+						t.Errorf("%v: Less is not transitive on %v, %v, %v", direction, a.NodeName, b.NodeName, c.NodeName)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestNodeRankerSort(t *testing.T) {
+	infos := func() NodeInfos {
+		return NodeInfos{
+			{NodeName: "d", RealCPURate: 52},
+			{NodeName: "c", RealCPURate: 11},
+			{NodeName: "a", RealCPURate: 54},
+			{NodeName: "b", RealCPURate: 12},
+			{NodeName: "e", RealCPURate: 80},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		direction config.RankingDirection
+		want      []string
+	}{
+		{
+			name:      "spread",
+			direction: config.SpreadRanking,
+			want:      []string{"b", "c", "a", "d", "e"},
+		},
+		{
+			name:      "binpack",
+			direction: config.BinPackRanking,
+			want:      []string{"e", "a", "d", "b", "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewNodeRanker(config.NodeRankingArgs{Direction: tt.direction, Rules: []config.RankingRule{cpuRule(5)}})
+			got := infos()
+			r.Sort(got)
+			for i, name := range tt.want {
+				if got[i].NodeName != name {
+					t.Fatalf("Sort() node %d = %v, want %v", i, got[i].NodeName, name)
+				}
+			}
+		})
+	}
+}
+
+// TestScoreRank checks that the rank computed at PreScore is what sets
+// apart nodes with the same usage.
+func TestScoreRank(t *testing.T) {
+	dp := &DynamicPlugin{
+		DynamicArgs: &config.DynamicArgs{
+			CPUWeight:    1,
+			MemoryWeight: 1,
+			Ranking:      config.NodeRankingArgs{Direction: config.SpreadRanking, Weight: 2},
+		},
+	}
+
+	infos := map[string]NodeInfo{
+		"a": {NodeName: "a", RealCPURate: 40, RealMemoryRate: 40},
+		"b": {NodeName: "b", RealCPURate: 40, RealMemoryRate: 40},
+		"c": {NodeName: "c", RealCPURate: 40, RealMemoryRate: 40},
+	}
+	state := framework.NewCycleState()
+	state.Write(preScoreStateKey, &preScoreState{
+		nodeInfos: infos,
+		rank:      map[string]int{"b": 0, "c": 1, "a": 2},
+	})
+
+	scores := make(map[string]int64, len(infos))
+	for name := range infos {
+		score, status := dp.Score(context.Background(), state, nil, name)
+		if !status.IsSuccess() {
+			t.Fatalf("Score(%v) status = %v", name, status)
+		}
+		scores[name] = score
+	}
+	if !(scores["b"] > scores["c"] && scores["c"] > scores["a"]) {
+		t.Errorf("Score() = %v, want the scores to follow the rank b, c, a", scores)
+	}
+
+	// Without a rank the scores only depend on the usage.
+	dp.DynamicArgs.Ranking.Weight = 0
+	for name := range infos {
+		score, _ := dp.Score(context.Background(), state, nil, name)
+		if score != leastUsedScore(40) {
+			t.Errorf("Score(%v) = %v without ranking weight, want %v", name, score, leastUsedScore(40))
+		}
+	}
+}
+
+// TestNodeInfoJSON checks that the debug dump of a node names every field
+// in camelCase, like the rest of the dump.
+func TestNodeInfoJSON(t *testing.T) {
+	data, err := json.Marshal(NodeInfo{
+		NodeName:  "node-1",
+		Resources: map[corev1.ResourceName]ResourceRate{"nvidia.com/gpu": {Real: 50, HasReal: true, Request: 25}},
+	})
+	if err != nil {
+		t.Fatalf("json.Marshal() err: %v", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("json.Unmarshal() err: %v", err)
+	}
+	for _, key := range []string{"nodeName", "realCPURate", "requestMemoryRate", "remainAllocatableCPU", "metricsTimestamp", "stale", "degraded", "resources"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("NodeInfo JSON %s has no %q field", data, key)
+		}
+	}
+	if got, want := string(fields["resources"]), `{"nvidia.com/gpu":{"real":50,"hasReal":true,"request":25}}`; got != want {
+		t.Errorf("resources = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,217 @@
+package dynamic
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	schedutil "k8s.io/kubernetes/pkg/scheduler/util"
+
+	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
+)
+
+// DynamicSchedulingCondition is set on the pods the Dynamic plugin keeps
+// pending, its message explains why. It turns True at Reserve once the pod
+// gets a node.
+const DynamicSchedulingCondition v1.PodConditionType = "DynamicScheduling"
+
+// Reasons of the Filter rejections.
+const (
+	reasonNodePressure    = "NodePressure"
+	reasonRequestCPU      = "RequestCPU"
+	reasonRequestMemory   = "RequestMemory"
+	reasonRequestResource = "RequestResource"
+	reasonRealResource    = "RealResource"
+	reasonStaleMetrics    = "StaleMetrics"
+	reasonRealCPU         = "RealCPU"
+	reasonRealMemory      = "RealMemory"
+)
+
+// reasonDescriptions describe the nodes rejected for each reason.
+var reasonDescriptions = map[string]string{
+	reasonNodePressure:    "under pressure",
+	reasonRequestCPU:      "over request cpu tolerance",
+	reasonRequestMemory:   "over request memory tolerance",
+	reasonRequestResource: "over request tolerance of an extra resource",
+	reasonRealResource:    "over real tolerance of an extra resource",
+	reasonStaleMetrics:    "with missing or stale metrics",
+	reasonRealCPU:         "over real cpu tolerance",
+	reasonRealMemory:      "over real memory tolerance",
+}
+
+// rejection explains why Filter rejects a node.
+type rejection struct {
+	reason  string
+	message string
+	// rate is the rate over the limit, it is empty for the rejections that
+	// are not about a rate.
+	rate         string
+	usage, limit float64
+}
+
+func newRateRejection(reason, rate string, usage, limit float64) *rejection {
+	return &rejection{
+		reason:  reason,
+		message: fmt.Sprintf("%s%s rate > %v", strings.ToUpper(rate[:1]), rate[1:], limit),
+		rate:    rate,
+		usage:   usage,
+		limit:   limit,
+	}
+}
+
+// PostFilter explains why the nodes rejected by Filter don't fit the pod.
+// It counts the rejections by reason, finds the node nearest to passing,
+// and publishes the summary as a pod condition and an Event. It never
+// makes the pod schedulable, so it should run before the preemption. In
+// Shadow mode Filter rejects no node, so it only records the decision.
+func (dp *DynamicPlugin) PostFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod, filteredNodeStatusMap framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	if dp.DynamicArgs.Mode == config.ShadowMode {
+		dp.recordShadowDecision(ctx, state, pod)
+		return nil, framework.NewStatus(framework.Unschedulable)
+	}
+
+	rejections := make(map[string]*rejection)
+	for nodeName, status := range filteredNodeStatusMap {
+		if status.FailedPlugin() != dp.Name() {
+			continue
+		}
+		nodeInfo, err := dp.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+		if err != nil || nodeInfo.Node() == nil {
+			continue
+		}
+		// The node may have cooled down since Filter, then it's not
+		// counted.
+		if r := dp.evaluate(ctx, pod, nodeInfo.Node(), nodeInfo); r != nil {
+			rejections[nodeName] = r
+		}
+	}
+
+	if len(rejections) == 0 {
+		return nil, framework.NewStatus(framework.Unschedulable)
+	}
+
+	summary := rejectionSummary(rejections, len(filteredNodeStatusMap))
+	message := diagnosis(rejections, len(filteredNodeStatusMap))
+	// Rejections before the cache is ready may not outlive the first
+	// scrape, they are not worth an Event.
+	if err := dp.NodeCache.Ready(); err != nil {
+		klog.FromContext(ctx).V(4).Info("Not publishing diagnosis, node cache not ready", "pod", klog.KObj(pod), "err", err)
+	} else {
+		dp.publishDiagnosis(ctx, pod, summary, message)
+	}
+	return nil, framework.NewStatus(framework.Unschedulable, message)
+}
+
+// diagnosis is the rejection summary followed by the node nearest to
+// passing, e.g. "3/5 nodes rejected by Dynamic: 2 over real cpu tolerance,
+// 1 with missing or stale metrics; nearest node node-1 is 2.3 points over
+// its real cpu tolerance 50".
+func diagnosis(rejections map[string]*rejection, total int) string {
+	res := rejectionSummary(rejections, total)
+	if nearest := nearestRejection(rejections); nearest != "" {
+		res += "; " + nearest
+	}
+	return res
+}
+
+// nearestRejection describes the node nearest to passing among the nodes
+// rejected over a rate, empty if there is none.
+func nearestRejection(rejections map[string]*rejection) string {
+	var nearest *rejection
+	var nearestNode string
+	for nodeName, r := range rejections {
+		if r.rate != "" && (nearest == nil || r.usage-r.limit < nearest.usage-nearest.limit ||
+			r.usage-r.limit == nearest.usage-nearest.limit && nodeName < nearestNode) {
+			nearest, nearestNode = r, nodeName
+		}
+	}
+	if nearest == nil {
+		return ""
+	}
+	return fmt.Sprintf("nearest node %s is %.1f points over its %s tolerance %v",
+		nearestNode, nearest.usage-nearest.limit, nearest.rate, nearest.limit)
+}
+
+// rejectionSummary counts the rejections of total nodes by reason.
+func rejectionSummary(rejections map[string]*rejection, total int) string {
+	counts := make(map[string]int)
+	for _, r := range rejections {
+		counts[r.reason]++
+	}
+
+	reasons := make([]string, 0, len(counts))
+	for reason := range counts {
+		reasons = append(reasons, reason)
+	}
+	// The most frequent first, the reason breaks ties.
+	sort.Slice(reasons, func(i, j int) bool {
+		if counts[reasons[i]] != counts[reasons[j]] {
+			return counts[reasons[i]] > counts[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+
+	parts := make([]string, len(reasons))
+	for i, reason := range reasons {
+		parts[i] = fmt.Sprintf("%d %s", counts[reason], reasonDescriptions[reason])
+	}
+
+	return fmt.Sprintf("%d/%d nodes rejected by Dynamic: %s", len(rejections), total, strings.Join(parts, ", "))
+}
+
+// publishDiagnosis records the diagnosis message as a warning Event and
+// sets the DynamicScheduling condition of the pod to the summary, which
+// leaves out the usage of the nearest node as it changes on every scrape.
+// Both are sent at most once per Events.Interval, and the condition only
+// when it changes.
+func (dp *DynamicPlugin) publishDiagnosis(ctx context.Context, pod *v1.Pod, summary, message string) {
+	if dp.eventLimiter.allow("pod/" + string(pod.UID)) {
+		dp.handle.EventRecorder().Eventf(pod, nil, v1.EventTypeWarning, "FailedDynamicScheduling", "Scheduling", "%s", message)
+	}
+
+	status := pod.Status.DeepCopy()
+	if !podutil.UpdatePodCondition(status, &v1.PodCondition{
+		Type:    DynamicSchedulingCondition,
+		Status:  v1.ConditionFalse,
+		Reason:  "NodesOverloaded",
+		Message: summary,
+	}) {
+		return
+	}
+	if !dp.eventLimiter.allow("pod-condition/" + string(pod.UID)) {
+		return
+	}
+	if err := schedutil.PatchPodStatus(ctx, dp.handle.ClientSet(), pod, status); err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to patch pod condition", "pod", klog.KObj(pod), "condition", DynamicSchedulingCondition)
+	}
+}
+
+// clearDiagnosis sets the DynamicScheduling condition of a pod that got a
+// node back to True, so that it no longer reports the nodes overloaded of
+// an earlier cycle. Pods without the condition are left untouched. The
+// patch is sent in the background so that it doesn't slow down the
+// scheduling cycle.
+func (dp *DynamicPlugin) clearDiagnosis(ctx context.Context, pod *v1.Pod) {
+	_, condition := podutil.GetPodCondition(&pod.Status, DynamicSchedulingCondition)
+	if condition == nil || condition.Status == v1.ConditionTrue {
+		return
+	}
+
+	status := pod.Status.DeepCopy()
+	podutil.UpdatePodCondition(status, &v1.PodCondition{
+		Type:   DynamicSchedulingCondition,
+		Status: v1.ConditionTrue,
+		Reason: "Scheduled",
+	})
+	logger := klog.FromContext(ctx)
+	go func() {
+		if err := schedutil.PatchPodStatus(context.Background(), dp.handle.ClientSet(), pod, status); err != nil {
+			logger.Error(err, "Failed to patch pod condition", "pod", klog.KObj(pod), "condition", DynamicSchedulingCondition)
+		}
+	}()
+}
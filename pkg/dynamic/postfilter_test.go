@@ -0,0 +1,143 @@
+package dynamic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
+)
+
+// fakeHandle is a framework.Handle that only serves a clientset, calling
+// any other method panics.
+type fakeHandle struct {
+	framework.Handle
+	clientSet kubernetes.Interface
+}
+
+func (h *fakeHandle) ClientSet() kubernetes.Interface {
+	return h.clientSet
+}
+
+func TestDiagnosis(t *testing.T) {
+	pressure := &rejection{reason: reasonNodePressure, message: "Node is under memory pressure"}
+	stale := &rejection{reason: reasonStaleMetrics, message: "Node metrics are missing or stale"}
+
+	tests := []struct {
+		name        string
+		rejections  map[string]*rejection
+		total       int
+		wantSummary string
+		wantNearest string
+	}{
+		{
+			name: "most frequent reason first",
+			rejections: map[string]*rejection{
+				"a": newRateRejection(reasonRealCPU, "real cpu", 60, 50),
+				"b": newRateRejection(reasonRealCPU, "real cpu", 52.3, 50),
+				"c": stale,
+				"d": newRateRejection(reasonRequestMemory, "request memory", 95, 90),
+			},
+			total:       5,
+			wantSummary: "4/5 nodes rejected by Dynamic: 2 over real cpu tolerance, 1 over request memory tolerance, 1 with missing or stale metrics",
+			wantNearest: "nearest node b is 2.3 points over its real cpu tolerance 50",
+		},
+		{
+			name: "reason breaks count ties",
+			rejections: map[string]*rejection{
+				"a": stale,
+				"b": pressure,
+				"c": newRateRejection(reasonRealMemory, "real memory", 80, 70),
+			},
+			total:       3,
+			wantSummary: "3/3 nodes rejected by Dynamic: 1 under pressure, 1 over real memory tolerance, 1 with missing or stale metrics",
+			wantNearest: "nearest node c is 10.0 points over its real memory tolerance 70",
+		},
+		{
+			name: "node name breaks distance ties",
+			rejections: map[string]*rejection{
+				"b": newRateRejection(reasonRealCPU, "real cpu", 55, 50),
+				"a": newRateRejection(reasonRealMemory, "real memory", 75, 70),
+				"c": newRateRejection(reasonRealCPU, "real cpu", 55, 50),
+			},
+			total:       3,
+			wantSummary: "3/3 nodes rejected by Dynamic: 2 over real cpu tolerance, 1 over real memory tolerance",
+			wantNearest: "nearest node a is 5.0 points over its real memory tolerance 70",
+		},
+		{
+			name: "rejections without a rate have no nearest node",
+			rejections: map[string]*rejection{
+				"a": pressure,
+				"b": stale,
+				"c": stale,
+			},
+			total:       4,
+			wantSummary: "3/4 nodes rejected by Dynamic: 2 with missing or stale metrics, 1 under pressure",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rejectionSummary(tt.rejections, tt.total); got != tt.wantSummary {
+				t.Errorf("rejectionSummary() = %q, want %q", got, tt.wantSummary)
+			}
+			if got := nearestRejection(tt.rejections); got != tt.wantNearest {
+				t.Errorf("nearestRejection() = %q, want %q", got, tt.wantNearest)
+			}
+
+			want := tt.wantSummary
+			if tt.wantNearest != "" {
+				want += "; " + tt.wantNearest
+			}
+			if got := diagnosis(tt.rejections, tt.total); got != want {
+				t.Errorf("diagnosis() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// TestReserveClearsDiagnosis checks that Reserve turns the DynamicScheduling
+// condition left by PostFilter back to True.
+func TestReserveClearsDiagnosis(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"},
+		Status: v1.PodStatus{
+			Conditions: []v1.PodCondition{{
+				Type:    DynamicSchedulingCondition,
+				Status:  v1.ConditionFalse,
+				Reason:  "NodesOverloaded",
+				Message: "1/1 nodes rejected by Dynamic: 1 over real cpu tolerance",
+			}},
+		},
+	}
+	cs := fake.NewSimpleClientset(pod)
+	dp := &DynamicPlugin{
+		handle:      &fakeHandle{clientSet: cs},
+		DynamicArgs: &config.DynamicArgs{Mode: config.EnforceMode},
+	}
+
+	if status := dp.Reserve(context.Background(), framework.NewCycleState(), pod, "node-1"); !status.IsSuccess() {
+		t.Fatalf("Reserve() status = %v", status)
+	}
+
+	var condition *v1.PodCondition
+	err := wait.PollImmediate(10*time.Millisecond, time.Second, func() (bool, error) {
+		got, err := cs.CoreV1().Pods(pod.Namespace).Get(context.Background(), pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		_, condition = podutil.GetPodCondition(&got.Status, DynamicSchedulingCondition)
+		return condition != nil && condition.Status == v1.ConditionTrue, nil
+	})
+	if err != nil {
+		t.Fatalf("DynamicScheduling condition = %+v, want it True: %v", condition, err)
+	}
+}
@@ -0,0 +1,70 @@
+package dynamic
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// pressureConditions are the node pressure conditions along with the taints
+// the node lifecycle controller derives from them.
+var pressureConditions = []struct {
+	condition corev1.NodeConditionType
+	taint     string
+}{
+	{condition: corev1.NodeMemoryPressure, taint: corev1.TaintNodeMemoryPressure},
+	{condition: corev1.NodeDiskPressure, taint: corev1.TaintNodeDiskPressure},
+	{condition: corev1.NodePIDPressure, taint: corev1.TaintNodePIDPressure},
+}
+
+// nodePressure returns the pressure conditions the node is under, read from
+// its conditions or from their taints.
+func nodePressure(node *corev1.Node) []corev1.NodeConditionType {
+	var pressure []corev1.NodeConditionType
+	for _, p := range pressureConditions {
+		if hasCondition(node, p.condition) || hasTaint(node, p.taint) {
+			pressure = append(pressure, p.condition)
+		}
+	}
+	return pressure
+}
+
+func hasCondition(node *corev1.Node, conditionType corev1.NodeConditionType) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == conditionType {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func hasTaint(node *corev1.Node, key string) bool {
+	for _, t := range node.Spec.Taints {
+		if t.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// updateNodePressure records when a node was last seen under pressure. A
+// node leaving pressure is recorded too, so the cooldown starts once the
+// pressure is gone.
+func (nc *NodeCache) updateNodePressure(oldObj, newObj interface{}) {
+	node, ok := newObj.(*corev1.Node)
+	if !ok {
+		return
+	}
+
+	pressured := len(nodePressure(node)) > 0
+	if old, ok := oldObj.(*corev1.Node); ok && len(nodePressure(old)) > 0 {
+		pressured = true
+	}
+	if !pressured {
+		return
+	}
+
+	nc.Lock()
+	nc.lastPressure[node.Name] = time.Now()
+	nc.Unlock()
+}
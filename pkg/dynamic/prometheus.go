@@ -0,0 +1,158 @@
+package dynamic
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	promapi "github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+
+	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
+)
+
+// NodePlaceholder is replaced by the node name in PromQL queries.
+const NodePlaceholder = "$node"
+
+// prometheusProvider reads node usage from the Prometheus HTTP api.
+type prometheusProvider struct {
+	api     promv1.API
+	queries map[corev1.ResourceName]string
+	// timestampQuery returns the time of the latest raw sample of a node.
+	timestampQuery string
+	timeout        time.Duration
+}
+
+// NewPrometheusProvider new Prometheus metrics provider
+func NewPrometheusProvider(args config.PrometheusArgs) (MetricsProvider, error) {
+	if args.Address == "" {
+		return nil, fmt.Errorf("prometheus address is required")
+	}
+
+	client, err := promapi.NewClient(promapi.Config{Address: args.Address})
+	if err != nil {
+		return nil, fmt.Errorf("create prometheus client for %v err: %w", args.Address, err)
+	}
+
+	queries := map[corev1.ResourceName]string{
+		corev1.ResourceCPU:    args.CPUQuery,
+		corev1.ResourceMemory: args.MemoryQuery,
+	}
+	for _, q := range args.ResourceQueries {
+		queries[q.Name] = q.Query
+	}
+
+	return &prometheusProvider{
+		api:            promv1.NewAPI(client),
+		queries:        queries,
+		timestampQuery: args.TimestampQuery,
+		timeout:        args.Timeout.Duration,
+	}, nil
+}
+
+func (p *prometheusProvider) NodeMetrics(ctx context.Context, nodeName string) (*metricsv1beta1.NodeMetrics, error) {
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	metrics := &metricsv1beta1.NodeMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+		Usage:      corev1.ResourceList{},
+	}
+
+	for name, query := range p.queries {
+		if query == "" {
+			continue
+		}
+
+		value, ts, err := p.query(ctx, nodeQuery(query, nodeName))
+		if err != nil {
+			return nil, fmt.Errorf("query %v usage of node %v err: %w", name, nodeName, err)
+		}
+
+		switch {
+		case name == corev1.ResourceCPU:
+			metrics.Usage[name] = *resource.NewMilliQuantity(int64(value*1000), resource.DecimalSI)
+		case name == corev1.ResourceMemory || name == corev1.ResourceEphemeralStorage || v1helper.IsHugePageResourceName(name):
+			metrics.Usage[name] = *resource.NewQuantity(int64(value), resource.BinarySI)
+		default:
+			metrics.Usage[name] = *resource.NewMilliQuantity(int64(value*1000), resource.DecimalSI)
+		}
+		if ts.After(metrics.Timestamp.Time) {
+			metrics.Timestamp = metav1.NewTime(ts)
+		}
+	}
+
+	// The samples of instant queries carry the evaluation time, the age of
+	// the usage is the one of the raw series.
+	if p.timestampQuery != "" {
+		vector, err := p.queryVector(ctx, nodeQuery(p.timestampQuery, nodeName))
+		if err != nil {
+			return nil, fmt.Errorf("query sample time of node %v err: %w", nodeName, err)
+		}
+		var latest model.SampleValue
+		for _, sample := range vector {
+			if sample.Value > latest {
+				latest = sample.Value
+			}
+		}
+		metrics.Timestamp = metav1.NewTime(time.Unix(0, int64(float64(latest)*float64(time.Second))))
+	}
+
+	return metrics, nil
+}
+
+func nodeQuery(query, nodeName string) string {
+	return strings.ReplaceAll(query, NodePlaceholder, nodeName)
+}
+
+// query runs an instant query and returns the sum of all returned series
+// and the evaluation time.
+func (p *prometheusProvider) query(ctx context.Context, query string) (float64, time.Time, error) {
+	vector, err := p.queryVector(ctx, query)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	var (
+		value float64
+		ts    time.Time
+	)
+	for _, sample := range vector {
+		value += float64(sample.Value)
+		if t := sample.Timestamp.Time(); t.After(ts) {
+			ts = t
+		}
+	}
+	return value, ts, nil
+}
+
+// queryVector runs an instant query that must return a non empty vector.
+func (p *prometheusProvider) queryVector(ctx context.Context, query string) (model.Vector, error) {
+	result, warnings, err := p.api.Query(ctx, query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if len(warnings) > 0 {
+		klog.FromContext(ctx).V(3).Info("Prometheus query returned warnings", "query", query, "warnings", warnings)
+	}
+
+	vector, ok := result.(model.Vector)
+	if !ok {
+		return nil, fmt.Errorf("want vector result, got %v", result.Type())
+	}
+	if len(vector) == 0 {
+		return nil, fmt.Errorf("query %q returned no data", query)
+	}
+	return vector, nil
+}
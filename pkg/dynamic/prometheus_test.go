@@ -0,0 +1,183 @@
+package dynamic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
+)
+
+const (
+	testCPUQuery       = `cpu{node="$node"}`
+	testMemoryQuery    = `memory{node="$node"}`
+	testTimestampQuery = `max(timestamp(memory{node="$node"}))`
+)
+
+// newTestPrometheus serves the query api, replying to each query with the
+// data of responses or a 400 for unknown queries.
+func newTestPrometheus(t *testing.T, responses map[string]string, delay time.Duration) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		data, ok := responses[r.Form.Get("query")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"status":"error","errorType":"bad_data","error":"unknown query %q"}`, r.Form.Get("query"))
+			return
+		}
+		fmt.Fprintf(w, `{"status":"success","data":%s}`, data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func vectorData(samples ...string) string {
+	return fmt.Sprintf(`{"resultType":"vector","result":[%s]}`, strings.Join(samples, ","))
+}
+
+func sample(evaluation int64, value string) string {
+	return fmt.Sprintf(`{"metric":{"node":"node-1"},"value":[%d,"%s"]}`, evaluation, value)
+}
+
+func TestPrometheusNodeMetrics(t *testing.T) {
+	evaluation := time.Now().Unix()
+	scraped := evaluation - 600
+
+	tests := []struct {
+		name           string
+		responses      map[string]string
+		timestampQuery string
+		delay          time.Duration
+		wantCPU        string
+		wantMemory     string
+		wantTimestamp  time.Time
+		wantErr        string
+	}{
+		{
+			name: "vector",
+			responses: map[string]string{
+				`cpu{node="node-1"}`:                    vectorData(sample(evaluation, "1.5"), sample(evaluation, "0.5")),
+				`memory{node="node-1"}`:                 vectorData(sample(evaluation, "1073741824")),
+				`max(timestamp(memory{node="node-1"}))`: vectorData(sample(evaluation, fmt.Sprint(scraped))),
+			},
+			timestampQuery: testTimestampQuery,
+			wantCPU:        "2",
+			wantMemory:     "1Gi",
+			wantTimestamp:  time.Unix(scraped, 0),
+		},
+		{
+			name: "no timestamp query uses the evaluation time",
+			responses: map[string]string{
+				`cpu{node="node-1"}`:    vectorData(sample(evaluation, "0.25")),
+				`memory{node="node-1"}`: vectorData(sample(evaluation, "1024")),
+			},
+			wantCPU:       "250m",
+			wantMemory:    "1Ki",
+			wantTimestamp: time.Unix(evaluation, 0),
+		},
+		{
+			name: "empty vector",
+			responses: map[string]string{
+				`cpu{node="node-1"}`:    vectorData(),
+				`memory{node="node-1"}`: vectorData(sample(evaluation, "1024")),
+			},
+			wantErr: "returned no data",
+		},
+		{
+			name: "empty timestamp vector",
+			responses: map[string]string{
+				`cpu{node="node-1"}`:                    vectorData(sample(evaluation, "0.25")),
+				`memory{node="node-1"}`:                 vectorData(sample(evaluation, "1024")),
+				`max(timestamp(memory{node="node-1"}))`: vectorData(),
+			},
+			timestampQuery: testTimestampQuery,
+			wantErr:        "sample time",
+		},
+		{
+			name: "scalar",
+			responses: map[string]string{
+				`cpu{node="node-1"}`:    fmt.Sprintf(`{"resultType":"scalar","result":[%d,"1"]}`, evaluation),
+				`memory{node="node-1"}`: vectorData(sample(evaluation, "1024")),
+			},
+			wantErr: "want vector result, got scalar",
+		},
+		{
+			name: "matrix",
+			responses: map[string]string{
+				`cpu{node="node-1"}`:    fmt.Sprintf(`{"resultType":"matrix","result":[{"metric":{},"values":[[%d,"1"]]}]}`, evaluation),
+				`memory{node="node-1"}`: vectorData(sample(evaluation, "1024")),
+			},
+			wantErr: "want vector result, got matrix",
+		},
+		{
+			name: "timeout",
+			responses: map[string]string{
+				`cpu{node="node-1"}`:    vectorData(sample(evaluation, "0.25")),
+				`memory{node="node-1"}`: vectorData(sample(evaluation, "1024")),
+			},
+			delay:   time.Second,
+			wantErr: "context deadline exceeded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestPrometheus(t, tt.responses, tt.delay)
+			p, err := NewPrometheusProvider(config.PrometheusArgs{
+				Address:        server.URL,
+				CPUQuery:       testCPUQuery,
+				MemoryQuery:    testMemoryQuery,
+				TimestampQuery: tt.timestampQuery,
+				Timeout:        metav1.Duration{Duration: 100 * time.Millisecond},
+			})
+			if err != nil {
+				t.Fatalf("NewPrometheusProvider() err: %v", err)
+			}
+
+			metrics, err := p.NodeMetrics(context.Background(), "node-1")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NodeMetrics() err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NodeMetrics() err: %v", err)
+			}
+
+			if got := metrics.Usage[corev1.ResourceCPU]; got.String() != tt.wantCPU {
+				t.Errorf("cpu usage = %v, want %v", got.String(), tt.wantCPU)
+			}
+			if got := metrics.Usage[corev1.ResourceMemory]; got.String() != tt.wantMemory {
+				t.Errorf("memory usage = %v, want %v", got.String(), tt.wantMemory)
+			}
+			if !metrics.Timestamp.Time.Equal(tt.wantTimestamp) {
+				t.Errorf("timestamp = %v, want %v", metrics.Timestamp.Time, tt.wantTimestamp)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package dynamic
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rest "k8s.io/client-go/rest"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
+
+	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
+)
+
+// MetricsProvider provides the real usage of nodes.
+type MetricsProvider interface {
+	// NodeMetrics returns the current usage of the given node.
+	NodeMetrics(ctx context.Context, nodeName string) (*metricsv1beta1.NodeMetrics, error)
+}
+
+// NodeMetricsLister is implemented by the metrics providers that can fetch
+// the usage of all nodes in one call.
+type NodeMetricsLister interface {
+	// ListNodeMetrics returns the current usage of all nodes, fetched in
+	// pages of pageSize.
+	ListNodeMetrics(ctx context.Context, pageSize int64) ([]*metricsv1beta1.NodeMetrics, error)
+}
+
+// PodMetricsLister is implemented by the metrics providers that can fetch
+// the usage of all pods.
+type PodMetricsLister interface {
+	// ListPodMetrics returns the current usage of all pods, fetched in
+	// pages of pageSize.
+	ListPodMetrics(ctx context.Context, pageSize int64) ([]*metricsv1beta1.PodMetrics, error)
+}
+
+// NewMetricsProvider new metrics provider of the configured type
+func NewMetricsProvider(kc *rest.Config, args config.MetricsProviderArgs) (MetricsProvider, error) {
+	switch args.Type {
+	case config.MetricsServerProvider:
+		return NewMetricsServerProvider(kc)
+	case config.PrometheusProvider:
+		return NewPrometheusProvider(args.Prometheus)
+	}
+	return nil, fmt.Errorf("unknown metrics provider type %q", args.Type)
+}
+
+// metricsServerProvider reads node usage from the metrics.k8s.io api.
+type metricsServerProvider struct {
+	metricsClient metricsclientset.Interface
+}
+
+// NewMetricsServerProvider new metrics-server metrics provider
+func NewMetricsServerProvider(kc *rest.Config) (MetricsProvider, error) {
+	metricsClient, err := metricsclientset.NewForConfig(kc)
+	if err != nil {
+		return nil, err
+	}
+	return &metricsServerProvider{metricsClient: metricsClient}, nil
+}
+
+func (p *metricsServerProvider) NodeMetrics(ctx context.Context, nodeName string) (*metricsv1beta1.NodeMetrics, error) {
+	return p.metricsClient.MetricsV1beta1().NodeMetricses().Get(ctx, nodeName, metav1.GetOptions{})
+}
+
+func (p *metricsServerProvider) ListNodeMetrics(ctx context.Context, pageSize int64) ([]*metricsv1beta1.NodeMetrics, error) {
+	var res []*metricsv1beta1.NodeMetrics
+	opts := metav1.ListOptions{Limit: pageSize}
+	for {
+		list, err := p.metricsClient.MetricsV1beta1().NodeMetricses().List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			res = append(res, &list.Items[i])
+		}
+		if list.Continue == "" {
+			return res, nil
+		}
+		opts.Continue = list.Continue
+	}
+}
+
+func (p *metricsServerProvider) ListPodMetrics(ctx context.Context, pageSize int64) ([]*metricsv1beta1.PodMetrics, error) {
+	var res []*metricsv1beta1.PodMetrics
+	opts := metav1.ListOptions{Limit: pageSize}
+	for {
+		list, err := p.metricsClient.MetricsV1beta1().PodMetricses(metav1.NamespaceAll).List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			res = append(res, &list.Items[i])
+		}
+		if list.Continue == "" {
+			return res, nil
+		}
+		opts.Continue = list.Continue
+	}
+}
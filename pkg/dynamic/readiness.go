@@ -0,0 +1,87 @@
+package dynamic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+var (
+	errCacheNotSynced = errors.New("node and pod cache not synced yet")
+	errNoNodeMetrics  = errors.New("no node metrics scraped yet")
+)
+
+// cacheReadiness holds why the informers are not synced, nil once they
+// are, and whether a node metrics scrape succeeded.
+type cacheReadiness struct {
+	err     error
+	scraped bool
+	sync.RWMutex
+}
+
+func (r *cacheReadiness) set(err error) {
+	r.Lock()
+	defer r.Unlock()
+	r.err = err
+}
+
+func (r *cacheReadiness) setScraped() {
+	r.Lock()
+	defer r.Unlock()
+	r.scraped = true
+}
+
+// Ready returns an error until the informers are synced and a node metrics
+// scrape succeeded, or the failed scrapes turned the cache degraded.
+func (nc *NodeCache) Ready() error {
+	nc.readiness.RLock()
+	err, scraped := nc.readiness.err, nc.readiness.scraped
+	nc.readiness.RUnlock()
+
+	if err != nil {
+		return err
+	}
+	if !scraped && !nc.Degraded() {
+		return errNoNodeMetrics
+	}
+	return nil
+}
+
+// waitForCacheSync waits for the informers to sync, CacheSyncTimeout at a
+// time. Once a wait times out the readiness check reports it, and the
+// waiting goes on until the cache syncs or is closed. It returns false if
+// the cache is closed first.
+func (nc *NodeCache) waitForCacheSync(synced ...cache.InformerSynced) bool {
+	stopCtx, stop := wait.ContextForChannel(nc.stopCh)
+	defer stop()
+
+	for {
+		ctx, cancel := context.WithTimeout(stopCtx, nc.syncTimeout)
+		ok := cache.WaitForCacheSync(ctx.Done(), synced...)
+		cancel()
+		if ok {
+			return true
+		}
+		if stopCtx.Err() != nil {
+			return false
+		}
+
+		klog.ErrorS(nil, "Timed out waiting for node and pod cache sync", "timeout", nc.syncTimeout)
+		nc.readiness.set(fmt.Errorf("node and pod cache not synced after %v", nc.syncTimeout))
+	}
+}
+
+// NewCacheSyncCheck returns a readiness check failing until the node cache
+// is ready.
+func NewCacheSyncCheck(c Cache) healthz.HealthChecker {
+	return healthz.NamedCheck("dynamic-cache-sync", func(_ *http.Request) error {
+		return c.Ready()
+	})
+}
@@ -0,0 +1,64 @@
+package dynamic
+
+import (
+	"errors"
+	"testing"
+
+	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
+)
+
+func TestNodeCacheReady(t *testing.T) {
+	tests := []struct {
+		name string
+		// scrapes are the outcomes of the node metrics scrapes after the
+		// informers synced, nil if they never did.
+		scrapes []bool
+		wantErr error
+	}{
+		{
+			name:    "not synced",
+			wantErr: errCacheNotSynced,
+		},
+		{
+			name:    "synced without scrape",
+			scrapes: []bool{},
+			wantErr: errNoNodeMetrics,
+		},
+		{
+			name:    "failed scrapes under the failure threshold",
+			scrapes: []bool{false, false},
+			wantErr: errNoNodeMetrics,
+		},
+		{
+			name:    "failed scrapes turned degraded",
+			scrapes: []bool{false, false, false},
+		},
+		{
+			name:    "successful scrape",
+			scrapes: []bool{false, true},
+		},
+		{
+			name:    "failures after a successful scrape",
+			scrapes: []bool{true, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nc := &NodeCache{
+				degradation: config.DegradationArgs{FailureThreshold: 3},
+				readiness:   cacheReadiness{err: errCacheNotSynced},
+			}
+			if tt.scrapes != nil {
+				nc.readiness.set(nil)
+			}
+			for _, ok := range tt.scrapes {
+				nc.updateMetricsHealth(ok)
+			}
+
+			if err := nc.Ready(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Ready() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package dynamic
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/tanjunchen/tanjunchen-scheduler/pkg/names"
+)
+
+// ShadowDecisionAnnotation holds, in Shadow mode, the nodes Filter would
+// have rejected for the pod.
+const ShadowDecisionAnnotation = "dynamic.scheduler/shadow-decision"
+
+const shadowStateKey = "Shadow" + names.DynamicName
+
+// shadowState collects the rejections Filter ignores in Shadow mode during
+// a scheduling cycle.
+type shadowState struct {
+	rejections map[string]*rejection
+	// evaluated are the nodes Filter ran on. Filter may run twice on a
+	// node in a cycle, once with the nominated pods, so they are counted
+	// by name.
+	evaluated sets.String
+	sync.Mutex
+}
+
+// Clone the shadow state.
+func (s *shadowState) Clone() framework.StateData {
+	return s
+}
+
+// add records the result of Filter on a node, r is nil when the node fits.
+func (s *shadowState) add(nodeName string, r *rejection) {
+	s.Lock()
+	defer s.Unlock()
+	s.evaluated.Insert(nodeName)
+	if r != nil {
+		s.rejections[nodeName] = r
+	}
+}
+
+// shadowState returns the shadow state of the cycle, Filter runs in
+// parallel so the first node creates it under dp.shadowMu.
+func (dp *DynamicPlugin) shadowState(state *framework.CycleState) *shadowState {
+	if s := readShadowState(state); s != nil {
+		return s
+	}
+
+	dp.shadowMu.Lock()
+	defer dp.shadowMu.Unlock()
+
+	if s := readShadowState(state); s != nil {
+		return s
+	}
+	s := &shadowState{rejections: make(map[string]*rejection), evaluated: sets.NewString()}
+	state.Write(shadowStateKey, s)
+	return s
+}
+
+func readShadowState(state *framework.CycleState) *shadowState {
+	c, err := state.Read(shadowStateKey)
+	if err != nil {
+		return nil
+	}
+	s, _ := c.(*shadowState)
+	return s
+}
+
+// recordShadowDecision annotates the pod with the nodes Filter would have
+// rejected among the nodes it ran on. It is called at Reserve when the pod
+// got a node and at PostFilter when it didn't, one of which ends every
+// cycle. The patch is sent in the background so that it doesn't slow down
+// the scheduling cycle.
+func (dp *DynamicPlugin) recordShadowDecision(ctx context.Context, state *framework.CycleState, pod *v1.Pod) {
+	s := readShadowState(state)
+	if s == nil {
+		return
+	}
+
+	s.Lock()
+	if len(s.rejections) == 0 {
+		s.Unlock()
+		return
+	}
+	message := diagnosis(s.rejections, s.evaluated.Len())
+	s.Unlock()
+
+	logger := klog.FromContext(ctx)
+	logger.V(2).Info("Shadow decision", "pod", klog.KObj(pod), "decision", message)
+	if pod.Annotations[ShadowDecisionAnnotation] == message {
+		return
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{ShadowDecisionAnnotation: message},
+		},
+	})
+	if err != nil {
+		logger.Error(err, "Failed to build shadow decision patch", "pod", klog.KObj(pod))
+		return
+	}
+	go func() {
+		_, err := dp.handle.ClientSet().CoreV1().Pods(pod.Namespace).Patch(context.Background(), pod.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			logger.Error(err, "Failed to annotate pod with shadow decision", "pod", klog.KObj(pod))
+		}
+	}()
+}
@@ -0,0 +1,124 @@
+package dynamic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
+)
+
+// TestShadowStateAdd checks that a node Filter runs on twice in a cycle is
+// counted once.
+func TestShadowStateAdd(t *testing.T) {
+	dp := &DynamicPlugin{}
+	state := framework.NewCycleState()
+
+	hot := newRateRejection(reasonRealCPU, "real cpu", 60, 50)
+	dp.shadowState(state).add("a", hot)
+	dp.shadowState(state).add("a", hot)
+	dp.shadowState(state).add("b", nil)
+	dp.shadowState(state).add("b", nil)
+
+	s := readShadowState(state)
+	if s == nil {
+		t.Fatalf("cycle has no shadow state")
+	}
+	if got := s.evaluated.Len(); got != 2 {
+		t.Errorf("evaluated nodes = %v, want 2", got)
+	}
+	if len(s.rejections) != 1 || s.rejections["a"] != hot {
+		t.Errorf("rejections = %v, want only node a", s.rejections)
+	}
+	if got, want := diagnosis(s.rejections, s.evaluated.Len()), "1/2 nodes rejected by Dynamic: 1 over real cpu tolerance; nearest node a is 10.0 points over its real cpu tolerance 50"; got != want {
+		t.Errorf("diagnosis() = %q, want %q", got, want)
+	}
+}
+
+// TestShadowDecisionRecorded checks that the shadow decision is recorded
+// whether the pod got a node, at Reserve, or not, at PostFilter.
+func TestShadowDecisionRecorded(t *testing.T) {
+	const decision = "1/2 nodes rejected by Dynamic: 1 over real memory tolerance; nearest node b is 5.0 points over its real memory tolerance 70"
+
+	tests := []struct {
+		name       string
+		rejections map[string]*rejection
+		end        func(dp *DynamicPlugin, state *framework.CycleState, pod *v1.Pod)
+		want       string
+	}{
+		{
+			name:       "reserve",
+			rejections: map[string]*rejection{"b": newRateRejection(reasonRealMemory, "real memory", 75, 70)},
+			end: func(dp *DynamicPlugin, state *framework.CycleState, pod *v1.Pod) {
+				if status := dp.Reserve(context.Background(), state, pod, "a"); !status.IsSuccess() {
+					t.Errorf("Reserve() status = %v", status)
+				}
+			},
+			want: decision,
+		},
+		{
+			name:       "post filter",
+			rejections: map[string]*rejection{"b": newRateRejection(reasonRealMemory, "real memory", 75, 70)},
+			end: func(dp *DynamicPlugin, state *framework.CycleState, pod *v1.Pod) {
+				if _, status := dp.PostFilter(context.Background(), state, pod, nil); status.Code() != framework.Unschedulable {
+					t.Errorf("PostFilter() status = %v, want Unschedulable", status)
+				}
+			},
+			want: decision,
+		},
+		{
+			name: "no rejection",
+			end: func(dp *DynamicPlugin, state *framework.CycleState, pod *v1.Pod) {
+				dp.Reserve(context.Background(), state, pod, "a")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"}}
+			cs := fake.NewSimpleClientset(pod)
+			dp := &DynamicPlugin{
+				handle:      &fakeHandle{clientSet: cs},
+				DynamicArgs: &config.DynamicArgs{Mode: config.ShadowMode},
+			}
+
+			state := framework.NewCycleState()
+			dp.shadowState(state).add("a", nil)
+			for nodeName, r := range tt.rejections {
+				dp.shadowState(state).add(nodeName, r)
+			}
+			tt.end(dp, state, pod)
+
+			if tt.want == "" {
+				// Without rejections no patch is sent, not even in the
+				// background.
+				for _, action := range cs.Actions() {
+					if action.GetVerb() == "patch" {
+						t.Errorf("pod patched without rejections")
+					}
+				}
+				return
+			}
+
+			var got string
+			err := wait.PollImmediate(10*time.Millisecond, time.Second, func() (bool, error) {
+				p, err := cs.CoreV1().Pods(pod.Namespace).Get(context.Background(), pod.Name, metav1.GetOptions{})
+				if err != nil {
+					return false, err
+				}
+				got = p.Annotations[ShadowDecisionAnnotation]
+				return got == tt.want, nil
+			})
+			if err != nil {
+				t.Errorf("shadow decision = %q, want %q: %v", got, tt.want, err)
+			}
+		})
+	}
+}
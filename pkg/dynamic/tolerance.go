@@ -0,0 +1,165 @@
+package dynamic
+
+import (
+	"fmt"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
+
+	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
+)
+
+const (
+	// CPUToleranceAnnotation and MemoryToleranceAnnotation override the
+	// tolerances of the annotated node when NodeAnnotationOverrides is set.
+	CPUToleranceAnnotation    = "dynamic.scheduler/cpu-tolerance"
+	MemoryToleranceAnnotation = "dynamic.scheduler/memory-tolerance"
+)
+
+// tolerance holds the usage rates a node may reach and still accept a pod.
+type tolerance struct {
+	cpu    float64
+	memory float64
+}
+
+// toleranceOverride is a config.ToleranceOverride ready to match pods.
+type toleranceOverride struct {
+	namespaces         sets.String
+	selector           labels.Selector
+	priorityClassNames sets.String
+	qosClasses         sets.String
+	cpu                *float64
+	memory             *float64
+}
+
+// nodeToleranceProfile is a config.NodeToleranceProfile ready to match
+// nodes.
+type nodeToleranceProfile struct {
+	selector labels.Selector
+	cpu      *float64
+	memory   *float64
+}
+
+func newNodeToleranceProfiles(profiles []config.NodeToleranceProfile) ([]nodeToleranceProfile, error) {
+	res := make([]nodeToleranceProfile, 0, len(profiles))
+	for i, p := range profiles {
+		selector, err := metav1.LabelSelectorAsSelector(p.NodeSelector)
+		if err != nil {
+			return nil, fmt.Errorf("nodeProfiles[%d] node selector err: %w", i, err)
+		}
+		res = append(res, nodeToleranceProfile{
+			selector: selector,
+			cpu:      p.ToleranceCPURate,
+			memory:   p.ToleranceMemoryRate,
+		})
+	}
+	return res, nil
+}
+
+func newToleranceOverrides(overrides []config.ToleranceOverride) ([]toleranceOverride, error) {
+	res := make([]toleranceOverride, 0, len(overrides))
+	for i, o := range overrides {
+		selector := labels.Everything()
+		if o.LabelSelector != nil {
+			var err error
+			selector, err = metav1.LabelSelectorAsSelector(o.LabelSelector)
+			if err != nil {
+				return nil, fmt.Errorf("toleranceOverrides[%d] label selector err: %w", i, err)
+			}
+		}
+
+		qosClasses := sets.NewString()
+		for _, c := range o.QOSClasses {
+			qosClasses.Insert(string(c))
+		}
+
+		res = append(res, toleranceOverride{
+			namespaces:         sets.NewString(o.Namespaces...),
+			selector:           selector,
+			priorityClassNames: sets.NewString(o.PriorityClassNames...),
+			qosClasses:         qosClasses,
+			cpu:                o.ToleranceCPURate,
+			memory:             o.ToleranceMemoryRate,
+		})
+	}
+	return res, nil
+}
+
+func (o *toleranceOverride) matches(pod *v1.Pod) bool {
+	if o.namespaces.Len() > 0 && !o.namespaces.Has(pod.Namespace) {
+		return false
+	}
+	if !o.selector.Matches(labels.Set(pod.Labels)) {
+		return false
+	}
+	if o.priorityClassNames.Len() > 0 && !o.priorityClassNames.Has(pod.Spec.PriorityClassName) {
+		return false
+	}
+	if o.qosClasses.Len() > 0 && !o.qosClasses.Has(string(qos.GetPodQOS(pod))) {
+		return false
+	}
+	return true
+}
+
+// tolerance returns the tolerance of the pod on the node, a nil pod gets
+// the tolerance of the node. See config.DynamicArgs.NodeAnnotationOverrides
+// for the precedence.
+func (dp *DynamicPlugin) tolerance(logger klog.Logger, pod *v1.Pod, nodesStat *NodeInfo) tolerance {
+	t := tolerance{
+		cpu:    dp.DynamicArgs.ToleranceCPURate,
+		memory: dp.DynamicArgs.ToleranceMemoryRate,
+	}
+
+	for i := range dp.profiles {
+		p := &dp.profiles[i]
+		if p.selector.Matches(labels.Set(nodesStat.Labels)) {
+			t.set(p.cpu, p.memory)
+			break
+		}
+	}
+
+	for i := range dp.overrides {
+		o := &dp.overrides[i]
+		if pod != nil && o.matches(pod) {
+			t.set(o.cpu, o.memory)
+			break
+		}
+	}
+
+	if dp.DynamicArgs.NodeAnnotationOverrides {
+		t.set(annotationRate(logger, nodesStat, CPUToleranceAnnotation), annotationRate(logger, nodesStat, MemoryToleranceAnnotation))
+	}
+	return t
+}
+
+// set replaces the tolerances that are not nil.
+func (t *tolerance) set(cpu, memory *float64) {
+	if cpu != nil {
+		t.cpu = *cpu
+	}
+	if memory != nil {
+		t.memory = *memory
+	}
+}
+
+// annotationRate parses a rate in percent from a node annotation, it
+// returns nil if the annotation is missing or invalid. It runs for every
+// pod and node, so invalid annotations are only logged at V(4).
+func annotationRate(logger klog.Logger, nodesStat *NodeInfo, key string) *float64 {
+	value, ok := nodesStat.Annotations[key]
+	if !ok {
+		return nil
+	}
+
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil || rate < 0 || rate > 100 {
+		logger.V(4).Info("Ignoring node annotation that is not a rate in [0, 100]", "node", klog.KRef("", nodesStat.NodeName), "annotation", key, "value", value)
+		return nil
+	}
+	return &rate
+}
@@ -0,0 +1,185 @@
+package dynamic
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	config "github.com/tanjunchen/tanjunchen-scheduler/apis/config"
+)
+
+func TestTolerancePrecedence(t *testing.T) {
+	args := &config.DynamicArgs{
+		ToleranceCPURate:        60,
+		ToleranceMemoryRate:     70,
+		NodeAnnotationOverrides: true,
+		NodeProfiles: []config.NodeToleranceProfile{
+			{
+				NodeSelector:        &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "batch"}},
+				ToleranceCPURate:    pointer.Float64(80),
+				ToleranceMemoryRate: pointer.Float64(85),
+			},
+			{
+				NodeSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "batch"}},
+				ToleranceCPURate: pointer.Float64(10),
+			},
+		},
+		ToleranceOverrides: []config.ToleranceOverride{
+			{
+				Namespaces:       []string{"critical"},
+				ToleranceCPURate: pointer.Float64(40),
+			},
+			{
+				PriorityClassNames:  []string{"low"},
+				ToleranceCPURate:    pointer.Float64(90),
+				ToleranceMemoryRate: pointer.Float64(90),
+			},
+			{
+				QOSClasses:          []corev1.PodQOSClass{corev1.PodQOSGuaranteed},
+				ToleranceMemoryRate: pointer.Float64(50),
+			},
+			{
+				Namespaces:          []string{"critical"},
+				ToleranceCPURate:    pointer.Float64(20),
+				ToleranceMemoryRate: pointer.Float64(20),
+			},
+		},
+	}
+	profiles, err := newNodeToleranceProfiles(args.NodeProfiles)
+	if err != nil {
+		t.Fatalf("newNodeToleranceProfiles() err: %v", err)
+	}
+	overrides, err := newToleranceOverrides(args.ToleranceOverrides)
+	if err != nil {
+		t.Fatalf("newToleranceOverrides() err: %v", err)
+	}
+	dp := &DynamicPlugin{DynamicArgs: args, profiles: profiles, overrides: overrides}
+
+	batch := map[string]string{"pool": "batch"}
+	pod := func(namespace, priorityClassName string, guaranteed bool) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: namespace},
+			Spec: corev1.PodSpec{
+				PriorityClassName: priorityClassName,
+				Containers:        []corev1.Container{{Name: "app"}},
+			},
+		}
+		if guaranteed {
+			resources := corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			}
+			p.Spec.Containers[0].Resources = corev1.ResourceRequirements{Requests: resources, Limits: resources}
+		}
+		return p
+	}
+
+	tests := []struct {
+		name        string
+		pod         *corev1.Pod
+		labels      map[string]string
+		annotations map[string]string
+		want        tolerance
+	}{
+		{
+			name: "global",
+			pod:  pod("default", "", false),
+			want: tolerance{cpu: 60, memory: 70},
+		},
+		{
+			name:   "node without pod",
+			labels: batch,
+			want:   tolerance{cpu: 80, memory: 85},
+		},
+		{
+			name:   "first matching node profile",
+			pod:    pod("default", "", false),
+			labels: batch,
+			want:   tolerance{cpu: 80, memory: 85},
+		},
+		{
+			name: "cpu only override on the global tolerance",
+			pod:  pod("critical", "", false),
+			want: tolerance{cpu: 40, memory: 70},
+		},
+		{
+			name:   "cpu only override on a profiled node",
+			pod:    pod("critical", "", false),
+			labels: batch,
+			want:   tolerance{cpu: 40, memory: 85},
+		},
+		{
+			name: "override by priority class",
+			pod:  pod("default", "low", false),
+			want: tolerance{cpu: 90, memory: 90},
+		},
+		{
+			name: "override by qos class",
+			pod:  pod("default", "", true),
+			want: tolerance{cpu: 60, memory: 50},
+		},
+		{
+			name: "first matching override",
+			pod:  pod("critical", "low", true),
+			want: tolerance{cpu: 40, memory: 70},
+		},
+		{
+			name:        "annotations beat an override",
+			pod:         pod("default", "low", false),
+			labels:      batch,
+			annotations: map[string]string{CPUToleranceAnnotation: "30", MemoryToleranceAnnotation: "35.5"},
+			want:        tolerance{cpu: 30, memory: 35.5},
+		},
+		{
+			name:        "cpu only annotation",
+			pod:         pod("default", "low", false),
+			annotations: map[string]string{CPUToleranceAnnotation: "30"},
+			want:        tolerance{cpu: 30, memory: 90},
+		},
+		{
+			name:        "invalid annotations are ignored",
+			pod:         pod("critical", "", false),
+			annotations: map[string]string{CPUToleranceAnnotation: "high", MemoryToleranceAnnotation: ""},
+			want:        tolerance{cpu: 40, memory: 70},
+		},
+		{
+			name:        "out of range annotations are ignored",
+			pod:         pod("default", "", false),
+			labels:      batch,
+			annotations: map[string]string{CPUToleranceAnnotation: "101", MemoryToleranceAnnotation: "-1"},
+			want:        tolerance{cpu: 80, memory: 85},
+		},
+		{
+			name:        "annotations at the range bounds are valid",
+			pod:         pod("default", "", false),
+			annotations: map[string]string{CPUToleranceAnnotation: "0", MemoryToleranceAnnotation: "100"},
+			want:        tolerance{cpu: 0, memory: 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &NodeInfo{NodeName: "node-1", Labels: tt.labels, Annotations: tt.annotations}
+			if got := dp.tolerance(klog.Background(), tt.pod, info); got != tt.want {
+				t.Errorf("tolerance() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestToleranceAnnotationsDisabled checks that the node annotations are
+// ignored unless NodeAnnotationOverrides is set.
+func TestToleranceAnnotationsDisabled(t *testing.T) {
+	dp := &DynamicPlugin{DynamicArgs: &config.DynamicArgs{ToleranceCPURate: 60, ToleranceMemoryRate: 70}}
+	info := &NodeInfo{
+		NodeName:    "node-1",
+		Annotations: map[string]string{CPUToleranceAnnotation: "30", MemoryToleranceAnnotation: "30"},
+	}
+	if got, want := dp.tolerance(klog.Background(), nil, info), (tolerance{cpu: 60, memory: 70}); got != want {
+		t.Errorf("tolerance() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,172 @@
+package dynamic
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// WorkloadProfile is the real footprint of one replica of a workload.
+type WorkloadProfile struct {
+	// MeanCPU and PeakCPU are in millicores.
+	MeanCPU int64
+	PeakCPU int64
+	// MeanMemory and PeakMemory are in bytes.
+	MeanMemory int64
+	PeakMemory int64
+	// Samples is the number of scrapes the profile is built from.
+	Samples int
+}
+
+// workloadSample is the usage of the replicas of a workload in one scrape.
+type workloadSample struct {
+	timestamp  time.Time
+	meanCPU    float64
+	peakCPU    int64
+	meanMemory float64
+	peakMemory int64
+}
+
+// workloadProfiles holds the last samples of every workload.
+type workloadProfiles struct {
+	samples map[string][]workloadSample
+	sync.RWMutex
+}
+
+// WorkloadKey identifies the workload owning a pod as namespace/kind/name.
+// Pods of a ReplicaSet belong to its Deployment when the ReplicaSet name
+// carries the pod-template-hash. ok is false for pods without controller.
+func WorkloadKey(pod *corev1.Pod) (key string, ok bool) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "", false
+	}
+
+	kind, name := owner.Kind, owner.Name
+	if hash := pod.Labels["pod-template-hash"]; kind == "ReplicaSet" && hash != "" && strings.HasSuffix(name, "-"+hash) {
+		kind, name = "Deployment", strings.TrimSuffix(name, "-"+hash)
+	}
+	return pod.Namespace + "/" + kind + "/" + name, true
+}
+
+// scrapePodMetrics records the usage of every workload if the metrics
+// provider can list pod metrics.
+func (nc *NodeCache) scrapePodMetrics() {
+	lister, ok := nc.provider.(PodMetricsLister)
+	if !ok {
+		klog.V(3).InfoS("Metrics provider can't list pod metrics, skipping workload profiles")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), nc.scrapeArgs.Interval.Duration)
+	defer cancel()
+
+	start := time.Now()
+	podMetrics, err := lister.ListPodMetrics(ctx, nc.scrapeArgs.PageSize)
+	scrapeDuration.WithLabelValues(podScrapeTarget).Observe(time.Since(start).Seconds())
+	if err != nil {
+		scrapeErrors.WithLabelValues(podScrapeTarget).Inc()
+		klog.ErrorS(err, "Failed to list pod metrics")
+		return
+	}
+
+	usage := make(map[string][]corev1.ResourceList)
+	for _, m := range podMetrics {
+		pod, err := nc.podInformer.Lister().Pods(m.Namespace).Get(m.Name)
+		if err != nil {
+			continue
+		}
+		key, ok := WorkloadKey(pod)
+		if !ok {
+			continue
+		}
+		usage[key] = append(usage[key], podUsage(m))
+	}
+
+	now := time.Now()
+	nc.profiles.Lock()
+	defer nc.profiles.Unlock()
+
+	for key, replicas := range usage {
+		s := workloadSample{timestamp: now}
+		for _, u := range replicas {
+			cpu, memory := u.Cpu().MilliValue(), u.Memory().Value()
+			s.meanCPU += float64(cpu) / float64(len(replicas))
+			s.meanMemory += float64(memory) / float64(len(replicas))
+			if cpu > s.peakCPU {
+				s.peakCPU = cpu
+			}
+			if memory > s.peakMemory {
+				s.peakMemory = memory
+			}
+		}
+
+		samples := append(nc.profiles.samples[key], s)
+		if len(samples) > metricsWindowSize {
+			samples = samples[len(samples)-metricsWindowSize:]
+		}
+		nc.profiles.samples[key] = samples
+	}
+
+	// Forget the workloads that were not seen for a whole window.
+	retention := time.Duration(metricsWindowSize) * nc.scrapeArgs.Interval.Duration
+	for key, samples := range nc.profiles.samples {
+		if now.Sub(samples[len(samples)-1].timestamp) > retention {
+			delete(nc.profiles.samples, key)
+		}
+	}
+
+	klog.V(3).InfoS("Updated workload profiles", "workloads", len(usage), "podMetrics", len(podMetrics))
+}
+
+// GetWorkloadProfile get the usage profile of the workload owning the pod
+func (nc *NodeCache) GetWorkloadProfile(pod *corev1.Pod) (WorkloadProfile, bool) {
+	key, ok := WorkloadKey(pod)
+	if !ok {
+		return WorkloadProfile{}, false
+	}
+
+	nc.profiles.RLock()
+	defer nc.profiles.RUnlock()
+
+	samples := nc.profiles.samples[key]
+	if len(samples) == 0 {
+		return WorkloadProfile{}, false
+	}
+
+	var meanCPU, meanMemory float64
+	profile := WorkloadProfile{Samples: len(samples)}
+	for _, s := range samples {
+		meanCPU += s.meanCPU / float64(len(samples))
+		meanMemory += s.meanMemory / float64(len(samples))
+		if s.peakCPU > profile.PeakCPU {
+			profile.PeakCPU = s.peakCPU
+		}
+		if s.peakMemory > profile.PeakMemory {
+			profile.PeakMemory = s.peakMemory
+		}
+	}
+	profile.MeanCPU = int64(math.Round(meanCPU))
+	profile.MeanMemory = int64(math.Round(meanMemory))
+	return profile, true
+}
+
+// podUsage sums the usage of the containers of a pod.
+func podUsage(m *metricsv1beta1.PodMetrics) corev1.ResourceList {
+	res := corev1.ResourceList{}
+	for _, c := range m.Containers {
+		for name, q := range c.Usage {
+			total := res[name]
+			total.Add(q)
+			res[name] = total
+		}
+	}
+	return res
+}